@@ -0,0 +1,7 @@
+package main
+
+import "time-tracker/internal/peopleapimock"
+
+func main() {
+	peopleapimock.Run()
+}
@@ -0,0 +1,7 @@
+package main
+
+import "time-tracker/internal/app"
+
+func main() {
+	app.Reencrypt()
+}
@@ -2,17 +2,30 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 )
 
 type (
 	Config struct {
-		App  App
-		HTTP HTTP
-		Log  Log
-		DSN  DSN
-		API  API
+		App        App
+		HTTP       HTTP
+		Gateway    Gateway
+		Log        Log
+		DSN        DSN
+		API        API
+		Retention  Retention
+		Encryption Encryption
+		Enrichment Enrichment
+		Sync       Sync
+		Import     Import
+		Pagination Pagination
+		Calendar   Calendar
+		Invoice    Invoice
+		Budget     Budget
+		Webhook    Webhook
+		Outbox     Outbox
 	}
 
 	App struct {
@@ -26,6 +39,12 @@ type (
 		Port string `env-required:"true" env:"HTTP_PORT"`
 	}
 
+	// Gateway is the API gateway that authenticates callers, the X-Actor and X-Permissions headers it sets are
+	// trusted only on requests carrying its token in X-Gateway-Token.
+	Gateway struct {
+		Token string `env:"GATEWAY_TOKEN,notEmpty"`
+	}
+
 	Log struct {
 		Level string `env:"LOG_LEVEL"`
 	}
@@ -35,7 +54,94 @@ type (
 	}
 
 	API struct {
-		UserApiURl string `env-required:"true" env:"USER_API_URL"`
+		UserApiURl              string        `env-required:"true" env:"USER_API_URL"`
+		UserApiTimeout          time.Duration `env:"USER_API_TIMEOUT" envDefault:"4s"`
+		UserApiRetries          int           `env:"USER_API_RETRIES" envDefault:"3"`
+		UserApiBackoff          time.Duration `env:"USER_API_BACKOFF" envDefault:"200ms"`
+		UserApiBackoffMax       time.Duration `env:"USER_API_BACKOFF_MAX" envDefault:"2s"`
+		UserApiBreakerThreshold int           `env:"USER_API_BREAKER_THRESHOLD" envDefault:"5"`
+		UserApiBreakerCooldown  time.Duration `env:"USER_API_BREAKER_COOLDOWN" envDefault:"30s"`
+	}
+
+	Retention struct {
+		Period        time.Duration `env:"RETENTION_PERIOD" envDefault:"720h"`
+		PurgeInterval time.Duration `env:"RETENTION_PURGE_INTERVAL" envDefault:"1h"`
+	}
+
+	Enrichment struct {
+		Interval  time.Duration `env:"ENRICHMENT_INTERVAL" envDefault:"1m"`
+		Providers []string      `env:"ENRICHMENT_PROVIDERS" envSeparator:"," envDefault:"api"` // in priority order: api, file
+		FilesDir  string        `env:"ENRICHMENT_FILES_DIR"`
+	}
+
+	Sync struct {
+		Interval  time.Duration `env:"PROFILE_SYNC_INTERVAL" envDefault:"24h"`
+		BatchSize int           `env:"PROFILE_SYNC_BATCH_SIZE" envDefault:"100"`
+		RateLimit float64       `env:"PROFILE_SYNC_RATE_LIMIT" envDefault:"5"` // requests per second
+	}
+
+	Import struct {
+		Workers int `env:"USER_IMPORT_WORKERS" envDefault:"8"` // concurrent enrichment lookups
+		MaxRows int `env:"USER_IMPORT_MAX_ROWS" envDefault:"5000"`
+		// time entries imported from other trackers, see pkg/tracker
+		TaskMaxRows  int    `env:"TASK_IMPORT_MAX_ROWS" envDefault:"20000"`
+		TaskTimezone string `env:"TASK_IMPORT_TIMEZONE" envDefault:"Europe/Moscow"` // of exports imported without a timezone
+	}
+
+	Pagination struct {
+		MaxLimit int `env:"PAGINATION_MAX_LIMIT" envDefault:"100"`
+	}
+
+	Calendar struct {
+		Window    time.Duration `env:"CALENDAR_WINDOW" envDefault:"720h"` // default period of the feed before now
+		MaxWindow time.Duration `env:"CALENDAR_MAX_WINDOW" envDefault:"8760h"`
+		Timezone  string        `env:"CALENDAR_TIMEZONE" envDefault:"Europe/Moscow"`
+	}
+
+	Invoice struct {
+		NumberPrefix string  `env:"INVOICE_NUMBER_PREFIX" envDefault:"INV-"`
+		Currency     string  `env:"INVOICE_CURRENCY" envDefault:"RUB"`
+		TaxRate      float64 `env:"INVOICE_TAX_RATE" envDefault:"0"` // percent
+		Issuer       string  `env:"INVOICE_ISSUER"`                  // printed at the top of invoices
+	}
+
+	Budget struct {
+		Thresholds []int  `env:"BUDGET_THRESHOLDS" envSeparator:"," envDefault:"50,80,100"` // percent of the limit
+		Timezone   string `env:"BUDGET_TIMEZONE" envDefault:"Europe/Moscow"`                // of the budget periods
+		// where alerts go, any of: log, webhook
+		Notifiers      []string      `env:"BUDGET_NOTIFIERS" envSeparator:"," envDefault:"log"`
+		WebhookURL     string        `env:"BUDGET_WEBHOOK_URL"`
+		WebhookTimeout time.Duration `env:"BUDGET_WEBHOOK_TIMEOUT" envDefault:"5s"`
+		AlertInterval  time.Duration `env:"BUDGET_ALERT_INTERVAL" envDefault:"30s"` // of delivering pending alerts
+		BatchSize      int           `env:"BUDGET_ALERT_BATCH_SIZE" envDefault:"100"`
+	}
+
+	Webhook struct {
+		Timeout     time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"` // of a delivery attempt
+		MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+		Backoff     time.Duration `env:"WEBHOOK_BACKOFF" envDefault:"30s"` // before the first retry, doubles on every next one
+		BackoffMax  time.Duration `env:"WEBHOOK_BACKOFF_MAX" envDefault:"6h"`
+		Interval    time.Duration `env:"WEBHOOK_DELIVERY_INTERVAL" envDefault:"5s"` // of polling for due deliveries
+		BatchSize   int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+		Retention   time.Duration `env:"WEBHOOK_RETENTION" envDefault:"720h"` // of delivered and failed deliveries
+	}
+
+	Outbox struct {
+		// where domain events go, any of: log, webhook (to the webhook subscriptions), file
+		Publishers []string      `env:"OUTBOX_PUBLISHERS" envSeparator:"," envDefault:"webhook"`
+		File       string        `env:"OUTBOX_FILE" envDefault:"events.ndjson"` // NDJSON of the file publisher
+		Interval   time.Duration `env:"OUTBOX_RELAY_INTERVAL" envDefault:"1s"`
+		BatchSize  int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+		Lease      time.Duration `env:"OUTBOX_LEASE" envDefault:"1m"` // of claimed events, until a crashed relay's ones are due again
+		RetryDelay time.Duration `env:"OUTBOX_RETRY_DELAY" envDefault:"30s"`
+		Retention  time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h"` // of published events
+	}
+
+	Encryption struct {
+		// key id -> base64 encoded 32 byte key, e.g. "v1:...,v2:...", there are no defaults for any of the keys
+		Keys          map[string]string `env:"ENCRYPTION_KEYS,notEmpty" envSeparator:"," envKeyValSeparator:":"`
+		ActiveKey     string            `env:"ENCRYPTION_ACTIVE_KEY,notEmpty"`
+		BlindIndexKey string            `env:"BLIND_INDEX_KEY,notEmpty"`
 	}
 )
 
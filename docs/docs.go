@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/tasks": {
+        "/api/v1/audit": {
             "get": {
-                "description": "Task list",
+                "description": "Audit log of an entity. Personal data in diffs is masked without the pii:read permission",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,27 +25,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks / Задачи"
+                    "Audit / Журнал изменений"
                 ],
-                "summary": "Получение списка элементов \"Задача\"",
+                "summary": "Получение журнала изменений",
                 "parameters": [
                     {
+                        "enum": [
+                            "user",
+                            "task",
+                            "project",
+                            "invoice",
+                            "client",
+                            "webhook"
+                        ],
                         "type": "string",
-                        "name": "dateFrom",
+                        "name": "entity",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "name": "dateTo",
+                        "type": "integer",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "name": "userId",
-                        "in": "query",
-                        "required": true
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -54,7 +66,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Task"
+                                "$ref": "#/definitions/model.AuditRecord"
                             }
                         }
                     },
@@ -64,11 +76,34 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/v1.errorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/clients": {
+            "get": {
+                "description": "Client list ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients / Клиенты"
+                ],
+                "summary": "Получение списка клиентов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Client"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -79,7 +114,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create Task",
+                "description": "Create Client, names are unique in any case. The default rate and currency are used for invoices\nof the client's projects",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,17 +122,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks / Задачи"
+                    "Clients / Клиенты"
                 ],
-                "summary": "Создание элемента \"Задача\"",
+                "summary": "Создание клиента",
                 "parameters": [
                     {
-                        "description": "Task input",
+                        "description": "Client input",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.createTaskInput"
+                            "$ref": "#/definitions/v1.createClientInput"
                         }
                     }
                 ],
@@ -105,7 +140,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.createTaskResponse"
+                            "$ref": "#/definitions/v1.createClientResponse"
                         }
                     },
                     "400": {
@@ -114,8 +149,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/v1.errorResponse"
                         }
@@ -129,23 +164,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/{id}": {
+        "/api/v1/clients/{id}": {
             "get": {
-                "description": "Get Task",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get Client",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks / Задачи"
+                    "Clients / Клиенты"
                 ],
-                "summary": "Получение элемента \"Задача\"",
+                "summary": "Получение клиента",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Client ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -155,7 +187,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Task"
+                            "$ref": "#/definitions/model.Client"
                         }
                     },
                     "400": {
@@ -177,11 +209,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/tasks/{id}/complete": {
-            "post": {
-                "description": "Complete specified task",
+            },
+            "patch": {
+                "description": "Update Client, omitted fields are kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -189,23 +219,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks / Задачи"
+                    "Clients / Клиенты"
                 ],
-                "summary": "Завершение задачи",
+                "summary": "Изменение клиента",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Client ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Client input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.updateClientInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.completeTaskResponse"
+                            "$ref": "#/definitions/v1.updateClientResponse"
                         }
                     },
                     "400": {
@@ -235,59 +274,104 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users": {
-            "get": {
-                "description": "User list",
+        "/api/v1/invoices": {
+            "post": {
+                "description": "Bills the completed billable tasks created within the period that no invoice billed yet, optionally\nof a single project or of the projects of a client, and marks them invoiced. The client, given or\nthe one of the project, provides the rate, currency and customer the input leaves empty.\nLines bill every task (groupBy=task, default) or the tasks of a project at the same rate\n(groupBy=project). Tasks have no tags, so there is no grouping by tag, the project takes its place.\nAmounts and rates are in minor currency units, rates are per hour.\nInvoices are numbered sequentially within the year.\nThe invoice is returned as JSON, HTML or PDF depending on the Accept header. Admin only",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/html",
+                    "application/pdf"
                 ],
                 "tags": [
-                    "Users / Пользователи"
+                    "Invoices / Счета"
                 ],
-                "summary": "Получение списка элементов \"Пользователь\"",
+                "summary": "Выставление счёта",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Invoice input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.createInvoiceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Invoice"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "name": "name",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "name": "offset",
-                        "in": "query"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "name": "passportNumber",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "name": "patronymic",
-                        "in": "query"
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/invoices/{id}": {
+            "get": {
+                "description": "Invoice as JSON, HTML or PDF depending on the Accept header. Admin only",
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Invoices / Счета"
+                ],
+                "summary": "Получение счёта",
+                "parameters": [
                     {
-                        "type": "string",
-                        "name": "surname",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "$ref": "#/definitions/model.Invoice"
                         }
                     },
                     "400": {
@@ -296,6 +380,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -309,9 +399,38 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/api/v1/projects": {
+            "get": {
+                "description": "Project list ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects / Проекты"
+                ],
+                "summary": "Получение списка проектов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Project"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
             },
             "post": {
-                "description": "User",
+                "description": "Create Project, names are unique in any case. The project belongs to the client clientId, if given",
                 "consumes": [
                     "application/json"
                 ],
@@ -319,17 +438,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users / Пользователи"
+                    "Projects / Проекты"
                 ],
-                "summary": "Создание элемента \"Пользователь\"",
+                "summary": "Создание проекта",
                 "parameters": [
                     {
-                        "description": "User input",
+                        "description": "Project input",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.createUserInput"
+                            "$ref": "#/definitions/v1.createProjectInput"
                         }
                     }
                 ],
@@ -337,7 +456,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.createUserResponse"
+                            "$ref": "#/definitions/v1.createProjectResponse"
                         }
                     },
                     "400": {
@@ -346,6 +465,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.errorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -361,23 +486,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/{id}": {
+        "/api/v1/projects/{id}/budget": {
             "get": {
-                "description": "Get User",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Budget of the project, the limit is in minutes for hours budgets",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users / Пользователи"
+                    "Projects / Проекты"
                 ],
-                "summary": "Получение элемента \"Пользователь\"",
+                "summary": "Получение бюджета проекта",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -387,7 +509,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.ProjectBudget"
                         }
                     },
                     "400": {
@@ -410,8 +532,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete user",
+            "put": {
+                "description": "Creates or replaces the budget of the project. Hours budgets limit the tracked hours, money budgets\nthe time billed at rate, by default the default rate of the project's client, in minor currency units.\nThe limit applies to every week, month, quarter or year or to the project lifetime. Alerts are sent\nwhen the completed tasks of a period reach the BUDGET_THRESHOLDS percentages of the limit",
                 "consumes": [
                     "application/json"
                 ],
@@ -419,23 +541,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users / Пользователи"
+                    "Projects / Проекты"
                 ],
-                "summary": "Delete user",
+                "summary": "Установка бюджета проекта",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Budget input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.setBudgetInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.deleteUserResponse"
+                            "$ref": "#/definitions/v1.setBudgetResponse"
                         }
                     },
                     "400": {
@@ -458,41 +589,29 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Update user",
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
+                "description": "Delete the budget of the project",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users / Пользователи"
+                    "Projects / Проекты"
                 ],
-                "summary": "Update user",
+                "summary": "Удаление бюджета проекта",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Project ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "User input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.updateUserInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.updateUserResponse"
+                            "$ref": "#/definitions/v1.deleteBudgetResponse"
                         }
                     },
                     "400": {
@@ -515,70 +634,2611 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Task": {
-            "type": "object",
-            "properties": {
-                "completed": {
-                    "type": "boolean"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
+        },
+        "/api/v1/projects/{id}/budget/burndown": {
+            "get": {
+                "description": "Consumption of the budget period containing date day by day up to that day, in minutes for hours\nbudgets. ideal is the remaining limit at an even pace, lifetime budgets start at the first task",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects / Проекты"
+                ],
+                "summary": "Сгорание бюджета проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "now by default",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BudgetBurndown"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/client": {
+            "put": {
+                "description": "Moves the project to the client clientId, null leaves the project without a client",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Projects / Проекты"
+                ],
+                "summary": "Смена клиента проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Client",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.setProjectClientInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.setProjectClientResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/clients": {
+            "get": {
+                "description": "Tracked time per client in the period, the time of tasks without a client has a null client_id\nand comes last. With Accept text/csv or XLSX the report is exported,\ncolumns: client_id, name, projects, tasks, duration_hours, billable_hours",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Reports / Отчёты"
+                ],
+                "summary": "Отчёт по клиентам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "dateFrom",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "dateTo",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "userId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ClientWorklogEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/worklog": {
+            "get": {
+                "description": "Tracked time per user in the period, users of any status are included.\nWith Accept text/csv or XLSX the report is exported,\ncolumns: user_id, name, surname, patronymic, status, tasks, duration_hours",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Reports / Отчёты"
+                ],
+                "summary": "Отчёт по трудозатратам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "dateFrom",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "dateTo",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "userId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WorklogEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks": {
+            "get": {
+                "description": "Task list, sortable by id, description, duration, created_at (longest first by default).\nWith Accept text/csv or XLSX the whole list is exported ignoring limit and cursor,\ncolumns: id, user_id, project_id, description, duration_hours, completed, billable, invoice_id,\ncreated_at, updated_at, deleted_at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Получение списка элементов \"Задача\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "dateFrom",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "dateTo",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "withTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.pageResponse-model_Task"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of matching tasks, only with withTotal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create Task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Создание элемента \"Задача\"",
+                "parameters": [
+                    {
+                        "description": "Task input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.createTaskInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.createTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/import": {
+            "post": {
+                "description": "Imports the detailed CSV export of Toggl Track, Clockify or Harvest as completed tasks, the format\nis detected from the header unless given. Users are matched by email, then by full name in either\norder, projects are matched by name and created when missing. Entries are identified by a hash\nof their fields, so importing a file again skips the entries imported before.\nStatuses: created, valid (dry run), exists, duplicate, unknown_user, ambiguous_user, invalid, failed\nThe body is limited to TASK_IMPORT_MAX_ROWS entries and 32 MiB, a larger one is rejected with 413",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Импорт трудозатрат из других трекеров",
+                "parameters": [
+                    {
+                        "enum": [
+                            "toggl",
+                            "clockify",
+                            "harvest"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time zone of the exported times, TASK_IMPORT_TIMEZONE by default",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match rows without creating projects and tasks",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.importTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "get": {
+                "description": "Get Task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Получение элемента \"Задача\"",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted task (admin only)",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft delete specified task, invoiced tasks can't be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Удаление задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.deleteTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/complete": {
+            "post": {
+                "description": "Complete specified task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Завершение задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.completeTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/restore": {
+            "post": {
+                "description": "Restore soft deleted task (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks / Задачи"
+                ],
+                "summary": "Восстановление задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.restoreTaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "description": "User list. Name filters match both Cyrillic and Latin spellings (\"Ivanov\" finds \"Иванов\").\nSortable by id, name, surname, patronymic, created_at, results of q are ordered by relevance.\nPassport numbers and addresses are masked without the pii:read permission.\nTerminated users are listed only when requested by status, e.g. status=active,suspended,terminated.\nWith Accept text/csv or XLSX the whole list is exported ignoring limit and cursor, columns: id, name,\nsurname, patronymic, document_type, document_country, passport_number, address, email, status,\nenrichment_status, created_at, updated_at, deleted_at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Получение списка элементов \"Пользователь\"",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "documentCountry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "DocumentType and DocumentCountry filter by the document, passportNumber is matched within them",
+                        "name": "documentType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "passportNumber",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "patronymic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Query is a typo tolerant search across name, surname, patronymic and address, results are ordered by relevance",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status is a comma separated list of lifecycle statuses, active and suspended users by default",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "withTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.pageResponse-model_User"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of matching users, only with withTotal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Создание элемента \"Пользователь\"",
+                "parameters": [
+                    {
+                        "description": "User input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.createUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.createUserResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "People info API is unavailable, the user is enriched in background",
+                        "schema": {
+                            "$ref": "#/definitions/v1.createUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/import": {
+            "post": {
+                "description": "Bulk import from CSV (header passportNumber,documentType,documentCountry,name,surname,patronymic,address,email,\nonly passportNumber is required) or NDJSON of createUserInput objects.\nRows with a name and surname are taken as is, the rest are enriched.\nStatuses: created, valid (dry run), exists, duplicate, invalid, failed\nThe body is limited to USER_IMPORT_MAX_ROWS rows and 32 MiB, a larger one is rejected with 413",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Импорт пользователей",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Validate and enrich rows without creating users",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.importUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "description": "Get User. Passport number and address are masked without the pii:read permission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Получение элемента \"Пользователь\"",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted user (admin only)",
+                        "name": "includeDeleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.deleteUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.updateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.updateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/anonymize": {
+            "post": {
+                "description": "Erase personal data of the user and its audit history keeping task durations for reports\n(requires admin)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Анонимизация пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.anonymizeUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/calendar-token": {
+            "post": {
+                "description": "Issue a new token for the iCalendar feed of the user, the previous subscription link stops working.\nOnly the user (X-Actor \"user:{id}\") or an admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar / Календарь"
+                ],
+                "summary": "Ссылка на календарь пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.calendarTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/calendar.ics": {
+            "get": {
+                "description": "iCalendar feed with a VEVENT per task started within the rolling window, for calendar app subscriptions",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendar / Календарь"
+                ],
+                "summary": "Календарь пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token from calendar-token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Window in days before now",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/duplicates": {
+            "get": {
+                "description": "Users likely to be the same person, scored by name, surname, patronymic and address similarity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Поиск дубликатов пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal score from 0 to 1, 0.5 by default",
+                        "name": "minScore",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DuplicateCandidate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/export": {
+            "get": {
+                "description": "Zip archive with the profile, tasks and audit records of the user (requires pii:read)",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Выгрузка данных пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/merge": {
+            "post": {
+                "description": "Move the tasks of the source user to the user from the path and delete the source, invoiced tasks\nstay with the source (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Объединение пользователей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the user that stays",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Duplicate to merge",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.mergeUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.mergeUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/restore": {
+            "post": {
+                "description": "Restore soft deleted user (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Restore user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.restoreUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/status": {
+            "get": {
+                "description": "Lifecycle status changes of the user ordered by effective date, scheduled ones included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "История статусов пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.UserStatusChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Move the user to a lifecycle status from the given date (admin only). Only active users get new tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users / Пользователи"
+                ],
+                "summary": "Изменение статуса пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status change",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.changeUserStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.changeUserStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "Webhook subscription list without secrets. Admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks / Вебхуки"
+                ],
+                "summary": "Получение списка подписок на вебхуки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe URL to events, to all of them when events are empty. Every delivery is a POST with\nthe event JSON signed by the X-Webhook-Signature header: \"sha256=\" and hex HMAC-SHA256 of\n\"{X-Webhook-Timestamp}.{body}\" with the secret. The secret is generated when not given and\nreturned only here. Admin only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks / Вебхуки"
+                ],
+                "summary": "Создание подписки на вебхуки",
+                "parameters": [
+                    {
+                        "description": "Webhook input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.createWebhookInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "delete": {
+                "description": "Delete webhook subscription with its deliveries. Admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks / Вебхуки"
+                ],
+                "summary": "Удаление подписки на вебхуки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.deleteWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Last 100 deliveries of the subscription, newest first. Admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks / Вебхуки"
+                ],
+                "summary": "Получение доставок вебхука",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "description": "Queue a new delivery of the same event, it keeps the event ID so receivers can deduplicate.\nAdmin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks / Вебхуки"
+                ],
+                "summary": "Повторная доставка вебхука",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.errorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AuditRecord": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "description": "field -\u003e {before, after}",
+                    "type": "object"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BudgetBurndown": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "$ref": "#/definitions/model.ProjectBudget"
+                },
+                "consumed": {
+                    "type": "integer"
+                },
+                "period_end": {
+                    "description": "exclusive",
+                    "type": "string"
+                },
+                "period_key": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BurndownPoint"
+                    }
+                },
+                "remaining": {
+                    "description": "negative when over budget",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BurndownPoint": {
+            "type": "object",
+            "properties": {
+                "consumed": {
+                    "description": "up to the end of the day",
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "ideal": {
+                    "description": "remaining at an even pace through the period, nil for lifetime budgets",
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "of the limit at the end of the day",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Client": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "contact_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "default_rate": {
+                    "description": "per hour in minor currency units, zero for none",
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ClientWorklogEntry": {
+            "type": "object",
+            "properties": {
+                "billable_duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "projects": {
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DuplicateCandidate": {
+            "type": "object",
+            "properties": {
+                "address_score": {
+                    "type": "number"
+                },
+                "name_score": {
+                    "type": "number"
+                },
+                "patronymic_score": {
+                    "type": "number"
+                },
+                "score": {
+                    "description": "weighted sum of the field scores",
+                    "type": "number"
+                },
+                "surname_score": {
+                    "type": "number"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.Invoice": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.InvoiceLine"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "period_from": {
+                    "type": "string"
+                },
+                "period_to": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "integer"
+                },
+                "task_ids": {
+                    "description": "the billed tasks",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tax": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "description": "percent",
+                    "type": "number"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Project": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProjectBudget": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Task": {
+            "type": "object",
+            "properties": {
+                "billable": {
+                    "type": "boolean"
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
                 "duration": {
                     "description": "in minutes",
                     "type": "integer"
                 },
+                "external_id": {
+                    "description": "ExternalID identifies a time entry imported from another tracker, see TrackerImport",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "description": "set once the task is billed",
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "anonymized_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "document_country": {
+                    "description": "ISO 3166-1 alpha-2",
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "enrichment_status": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "merged_into_id": {
+                    "description": "MergedIntoID is the user that took over the tasks of this duplicate",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passport_number": {
+                    "description": "number of the identity document",
+                    "type": "string"
+                },
+                "patronymic": {
+                    "type": "string"
+                },
+                "profile_pinned": {
+                    "description": "ProfilePinned opts the user out of the periodic re-synchronisation with the People info API",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status is the lifecycle status in effect now, see UserStatusChange",
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "model.UserStatusChange": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_status": {
+                    "description": "of the last attempt",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WorklogEntry": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patronymic": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "integer"
                 },
                 "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "model.User": {
+        "v1.anonymizeUserResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.calendarTokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "path of the feed to subscribe to",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.changeUserStatusInput": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "effectiveFrom": {
+                    "description": "EffectiveFrom schedules the change, it takes effect immediately when omitted",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "terminated"
+                    ]
+                }
+            }
+        },
+        "v1.changeUserStatusResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.completeTaskResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.createClientInput": {
+            "type": "object",
+            "required": [
+                "currency",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "contactName": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "defaultRate": {
+                    "description": "per hour in minor currency units",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "v1.createClientResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.createInvoiceInput": {
             "type": "object",
+            "required": [
+                "dateFrom",
+                "dateTo"
+            ],
             "properties": {
-                "address": {
-                    "type": "string"
+                "clientId": {
+                    "type": "integer"
                 },
-                "created_at": {
+                "currency": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "customer": {
+                    "type": "string",
+                    "maxLength": 1024
                 },
-                "name": {
+                "dateFrom": {
                     "type": "string"
                 },
-                "passport_number": {
+                "dateTo": {
                     "type": "string"
                 },
-                "patronymic": {
-                    "type": "string"
+                "groupBy": {
+                    "type": "string",
+                    "enum": [
+                        "task",
+                        "project"
+                    ]
                 },
-                "surname": {
-                    "type": "string"
+                "notes": {
+                    "type": "string",
+                    "maxLength": 4096
                 },
-                "updated_at": {
-                    "type": "string"
+                "projectId": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "per hour in minor currency units",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "taxRate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "userRates": {
+                    "description": "user id -\u003e rate",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "v1.completeTaskResponse": {
+        "v1.createProjectInput": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "success": {
-                    "type": "boolean"
+                "clientId": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 256
+                }
+            }
+        },
+        "v1.createProjectResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
                 }
             }
         },
@@ -589,9 +3249,16 @@ const docTemplate = `{
                 "userId"
             ],
             "properties": {
+                "billable": {
+                    "description": "true by default",
+                    "type": "boolean"
+                },
                 "description": {
                     "type": "string"
                 },
+                "projectId": {
+                    "type": "integer"
+                },
                 "userId": {
                     "type": "integer"
                 }
@@ -611,7 +3278,35 @@ const docTemplate = `{
                 "passportNumber"
             ],
             "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "documentCountry": {
+                    "description": "ISO 3166-1 alpha-2, defaults to the country of the document type",
+                    "type": "string"
+                },
+                "documentType": {
+                    "type": "string",
+                    "enum": [
+                        "ru_passport",
+                        "foreign_passport",
+                        "residence_permit"
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
                 "passportNumber": {
+                    "description": "number of the document",
+                    "type": "string"
+                },
+                "patronymic": {
+                    "type": "string"
+                },
+                "surname": {
                     "type": "string"
                 }
             }
@@ -619,11 +3314,53 @@ const docTemplate = `{
         "v1.createUserResponse": {
             "type": "object",
             "properties": {
+                "enrichmentStatus": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 }
             }
         },
+        "v1.createWebhookInput": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 256,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "v1.deleteBudgetResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.deleteTaskResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "v1.deleteUserResponse": {
             "type": "object",
             "properties": {
@@ -632,6 +3369,14 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.deleteWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "v1.errorResponse": {
             "type": "object",
             "properties": {
@@ -643,12 +3388,296 @@ const docTemplate = `{
                 }
             }
         },
+        "v1.importTaskResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "projectId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "taskId": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.importTasksResponse": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "projects": {
+                    "description": "created, in the dry run to be created",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.importTaskResult"
+                    }
+                },
+                "summary": {
+                    "description": "status -\u003e rows",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "warnings": {
+                    "description": "failed budget checks, the tasks are imported",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.importUserResult": {
+            "type": "object",
+            "properties": {
+                "enrichmentStatus": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.importUsersResponse": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.importUserResult"
+                    }
+                },
+                "summary": {
+                    "description": "status -\u003e rows",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "v1.mergeUserInput": {
+            "type": "object",
+            "required": [
+                "sourceId"
+            ],
+            "properties": {
+                "sourceId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.mergeUserResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.pageResponse-model_Task": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Task"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.pageResponse-model_User": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.restoreTaskResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.restoreUserResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.setBudgetInput": {
+            "type": "object",
+            "required": [
+                "kind",
+                "limit",
+                "period"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "hours",
+                        "money"
+                    ]
+                },
+                "limit": {
+                    "description": "hours or minor currency units",
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "lifetime",
+                        "week",
+                        "month",
+                        "quarter",
+                        "year"
+                    ]
+                },
+                "rate": {
+                    "description": "per hour in minor currency units, money budgets only",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "v1.setBudgetResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.setProjectClientInput": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "description": "null leaves the project without a client",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.setProjectClientResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.updateClientInput": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "contactName": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "defaultRate": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 256,
+                    "minLength": 1
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "v1.updateClientResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "v1.updateUserInput": {
             "type": "object",
             "properties": {
                 "address": {
                     "type": "string"
                 },
+                "documentCountry": {
+                    "type": "string"
+                },
+                "documentType": {
+                    "type": "string",
+                    "enum": [
+                        "ru_passport",
+                        "foreign_passport",
+                        "residence_permit"
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -658,6 +3687,10 @@ const docTemplate = `{
                 "patronymic": {
                     "type": "string"
                 },
+                "profilePinned": {
+                    "description": "ProfilePinned keeps manual corrections from being overwritten by the People info API sync",
+                    "type": "boolean"
+                },
                 "surname": {
                     "type": "string"
                 }
@@ -1,16 +1,24 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 	"time-tracker/config"
 	"time-tracker/internal/repository"
 	"time-tracker/internal/service"
 	v1 "time-tracker/internal/transport/http/v1"
+	"time-tracker/pkg/envelope"
 	"time-tracker/pkg/httpserver"
 	"time-tracker/pkg/postgres"
+	"time-tracker/pkg/scheduler"
+	"time-tracker/pkg/webhook"
+
+	// the scratch image has no zoneinfo, calendar time zones are loaded from the embedded database
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
@@ -31,7 +39,7 @@ func Run() {
 	log.Info("Startin application...")
 	cfg, err := config.NewConfig()
 	if err != nil {
-		log.Fatalf("error init config", err)
+		log.Fatalf("error init config: %v", err)
 	}
 
 	// init Logger -- logrus
@@ -44,22 +52,122 @@ func Run() {
 		log.Fatal(fmt.Errorf("app - Run - pgdb.NewServices: %w", err))
 	}
 
+	// init Encryption
+	env, err := envelope.New(cfg.Encryption.Keys, cfg.Encryption.ActiveKey, cfg.Encryption.BlindIndexKey)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - envelope.New: %w", err))
+	}
+
+	// init Enrichment providers
+	peopleInfo, err := newPeopleInfo(cfg)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - newPeopleInfo: %w", err))
+	}
+
+	// init Calendar time zone
+	calendarLocation, err := time.LoadLocation(cfg.Calendar.Timezone)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - time.LoadLocation: %w", err))
+	}
+
+	// init Budget alerts
+	budgetNotifier, err := newBudgetNotifier(cfg)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - newBudgetNotifier: %w", err))
+	}
+	budgetLocation, err := time.LoadLocation(cfg.Budget.Timezone)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - time.LoadLocation: %w", err))
+	}
+
 	// init Repositories
 	log.Info("Initializing repositories...")
-	reps := repository.NewRepositories(pg)
+	reps := repository.NewRepositories(pg, env, cfg.Pagination.MaxLimit)
+
+	// passport numbers stored before the blind index are invisible to duplicate checks until they get one
+	backfilled, err := reps.User.BackfillPassportHashes(context.Background())
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - BackfillPassportHashes: %w", err))
+	}
+	if backfilled > 0 {
+		log.Infof("Backfilled passport hashes: %d", backfilled)
+	}
+
+	// init Outbox publishers
+	eventPublisher, closeEventPublisher, err := newEventPublisher(cfg, reps)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Run - newEventPublisher: %w", err))
+	}
 
 	// init Services
 	log.Info("Initializing services...")
 	deps := service.ServiceDeps{
 		Reps: reps,
-		ApiURLS: cfg.API,
+		PeopleInfo: peopleInfo,
+		Retention: cfg.Retention,
+		Sync: cfg.Sync,
+		Import: cfg.Import,
+		Calendar: cfg.Calendar,
+		CalendarLocation: calendarLocation,
+		Invoice: cfg.Invoice,
+		Budget: cfg.Budget,
+		Notifier: budgetNotifier,
+		BudgetLocation: budgetLocation,
+		Webhook: cfg.Webhook,
+		WebhookSender: webhook.New(cfg.Webhook.Timeout),
+		Outbox: cfg.Outbox,
+		Publisher: eventPublisher,
 	}
 	services := service.NewServices(deps)
 
 	// Gin handler
 	log.Info("Initializing handlers and routes...")
 	handler := gin.New()
-	v1.NewRouter(handler, services)
+	v1.NewRouter(handler, services, cfg.Gateway.Token)
+
+	// Background jobs
+	log.Info("Starting background jobs...")
+	purgeScheduler := scheduler.New(services.Retention.Purge,
+		scheduler.Interval(cfg.Retention.PurgeInterval),
+		scheduler.ErrorHandler(func(err error) {
+			log.Error(fmt.Errorf("app - Run - Retention.Purge: %w", err))
+		}),
+	)
+
+	enrichScheduler := scheduler.New(services.User.EnrichPendingUsers,
+		scheduler.Interval(cfg.Enrichment.Interval),
+		scheduler.ErrorHandler(func(err error) {
+			log.Error(fmt.Errorf("app - Run - User.EnrichPendingUsers: %w", err))
+		}),
+	)
+
+	syncScheduler := scheduler.New(services.ProfileSync.SyncProfiles,
+		scheduler.Interval(cfg.Sync.Interval),
+		scheduler.ErrorHandler(func(err error) {
+			log.Error(fmt.Errorf("app - Run - ProfileSync.SyncProfiles: %w", err))
+		}),
+	)
+
+	budgetAlertScheduler := scheduler.New(services.Budget.NotifyPendingAlerts,
+		scheduler.Interval(cfg.Budget.AlertInterval),
+		scheduler.ErrorHandler(func(err error) {
+			log.Error(fmt.Errorf("app - Run - Budget.NotifyPendingAlerts: %w", err))
+		}),
+	)
+
+	webhookScheduler := scheduler.New(services.Webhook.DeliverPending,
+		scheduler.Interval(cfg.Webhook.Interval),
+		scheduler.ErrorHandler(func(err error) {
+			log.Error(fmt.Errorf("app - Run - Webhook.DeliverPending: %w", err))
+		}),
+	)
+
+	outboxScheduler := scheduler.New(services.Outbox.Relay,
+		scheduler.Interval(cfg.Outbox.Interval),
+		scheduler.ErrorHandler(func(err error) {
+			log.Error(fmt.Errorf("app - Run - Outbox.Relay: %w", err))
+		}),
+	)
 
 	// HTTP server
 	log.Info("Starting http server...")
@@ -86,4 +194,39 @@ func Run() {
 	if err != nil {
 		log.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
+
+	err = purgeScheduler.Shutdown()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - purgeScheduler.Shutdown: %w", err))
+	}
+
+	err = enrichScheduler.Shutdown()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - enrichScheduler.Shutdown: %w", err))
+	}
+
+	err = syncScheduler.Shutdown()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - syncScheduler.Shutdown: %w", err))
+	}
+
+	err = budgetAlertScheduler.Shutdown()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - budgetAlertScheduler.Shutdown: %w", err))
+	}
+
+	err = webhookScheduler.Shutdown()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - webhookScheduler.Shutdown: %w", err))
+	}
+
+	err = outboxScheduler.Shutdown()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - outboxScheduler.Shutdown: %w", err))
+	}
+
+	err = closeEventPublisher()
+	if err != nil {
+		log.Error(fmt.Errorf("app - Run - closeEventPublisher: %w", err))
+	}
 }
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time-tracker/config"
+	"time-tracker/pkg/notify"
+)
+
+const (
+	notifierLog     = "log"
+	notifierWebhook = "webhook"
+)
+
+// newBudgetNotifier notifies budget alerts through every notifier listed in BUDGET_NOTIFIERS.
+func newBudgetNotifier(cfg *config.Config) (notify.Notifier, error) {
+	notifiers := make([]notify.Notifier, 0, len(cfg.Budget.Notifiers))
+	for _, name := range cfg.Budget.Notifiers {
+		switch strings.TrimSpace(name) {
+		case notifierLog:
+			notifiers = append(notifiers, notify.NewLog())
+		case notifierWebhook:
+			if cfg.Budget.WebhookURL == "" {
+				return nil, fmt.Errorf("BUDGET_WEBHOOK_URL is required by the webhook notifier")
+			}
+			notifiers = append(notifiers, notify.NewWebhook(cfg.Budget.WebhookURL, cfg.Budget.WebhookTimeout))
+		default:
+			return nil, fmt.Errorf("unknown budget notifier %q", name)
+		}
+	}
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("no budget notifiers configured")
+	}
+	return notify.Multi(notifiers...), nil
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time-tracker/config"
+	"time-tracker/pkg/peopleinfo"
+)
+
+const (
+	enrichmentProviderAPI  = "api"
+	enrichmentProviderFile = "file"
+)
+
+// newPeopleInfo chains the enrichment providers in the order listed in ENRICHMENT_PROVIDERS.
+func newPeopleInfo(cfg *config.Config) (peopleinfo.Provider, error) {
+	providers := make([]peopleinfo.Provider, 0, len(cfg.Enrichment.Providers))
+	for _, name := range cfg.Enrichment.Providers {
+		switch strings.TrimSpace(name) {
+		case enrichmentProviderAPI:
+			providers = append(providers, peopleinfo.New(cfg.API.UserApiURl,
+				peopleinfo.Timeout(cfg.API.UserApiTimeout),
+				peopleinfo.MaxRetries(cfg.API.UserApiRetries),
+				peopleinfo.Backoff(cfg.API.UserApiBackoff, cfg.API.UserApiBackoffMax),
+				peopleinfo.CircuitBreaker(cfg.API.UserApiBreakerThreshold, cfg.API.UserApiBreakerCooldown),
+			))
+		case enrichmentProviderFile:
+			provider, err := peopleinfo.NewFileProvider(cfg.Enrichment.FilesDir)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+		default:
+			return nil, fmt.Errorf("unknown enrichment provider %q", name)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no enrichment providers configured")
+	}
+	return peopleinfo.Chain(providers...), nil
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time-tracker/config"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/service"
+	"time-tracker/pkg/publish"
+)
+
+const (
+	publisherLog     = "log"
+	publisherWebhook = "webhook"
+	publisherFile    = "file"
+)
+
+// newEventPublisher relays the outbox events through every publisher listed in OUTBOX_PUBLISHERS, the returned
+// func releases the file of the file publisher.
+func newEventPublisher(cfg *config.Config, reps *repository.Repositories) (publish.Publisher, func() error, error) {
+	closeFile := func() error { return nil }
+	publishers := make([]publish.Publisher, 0, len(cfg.Outbox.Publishers))
+	for _, name := range cfg.Outbox.Publishers {
+		switch strings.TrimSpace(name) {
+		case publisherLog:
+			publishers = append(publishers, publish.NewLog())
+		case publisherWebhook:
+			publishers = append(publishers, service.NewWebhookPublisher(reps))
+		case publisherFile:
+			file, err := publish.NewFile(cfg.Outbox.File)
+			if err != nil {
+				return nil, nil, err
+			}
+			closeFile = file.Close
+			publishers = append(publishers, file)
+		default:
+			return nil, nil, fmt.Errorf("unknown outbox publisher %q", name)
+		}
+	}
+	if len(publishers) == 0 {
+		return nil, nil, fmt.Errorf("no outbox publishers configured")
+	}
+	return publish.Multi(publishers...), closeFile, nil
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time-tracker/config"
+	"time-tracker/internal/repository"
+	"time-tracker/pkg/envelope"
+	"time-tracker/pkg/postgres"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Reencrypt moves every stored passport number to the active encryption key.
+// Run it after adding a new key to ENCRYPTION_KEYS and switching ENCRYPTION_ACTIVE_KEY,
+// the retired key can be removed once it is done.
+func Reencrypt() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("error init config: %v", err)
+	}
+
+	SetLogger(cfg.Log.Level)
+
+	pg, err := postgres.New(cfg.DSN.Database)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Reencrypt - postgres.New: %w", err))
+	}
+
+	env, err := envelope.New(cfg.Encryption.Keys, cfg.Encryption.ActiveKey, cfg.Encryption.BlindIndexKey)
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Reencrypt - envelope.New: %w", err))
+	}
+
+	reps := repository.NewRepositories(pg, env, cfg.Pagination.MaxLimit)
+
+	log.Infof("Re-encrypting passport numbers with key %s...", cfg.Encryption.ActiveKey)
+	processed, err := reps.User.ReencryptPassportNumbers(context.Background())
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - Reencrypt - ReencryptPassportNumbers: %w", err))
+	}
+	log.Infof("Re-encrypted passport numbers: %d", processed)
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityUser    = "user"
+	AuditEntityTask    = "task"
+	AuditEntityProject = "project"
+	AuditEntityInvoice = "invoice"
+	AuditEntityClient  = "client"
+	AuditEntityWebhook = "webhook"
+
+	AuditActionCreate    = "create"
+	AuditActionUpdate    = "update"
+	AuditActionDelete    = "delete"
+	AuditActionComplete  = "complete"
+	AuditActionRestore   = "restore"
+	AuditActionPurge     = "purge"
+	AuditActionAnonymize = "anonymize"
+	AuditActionResync    = "resync"
+	AuditActionMerge     = "merge"
+	AuditActionStatus    = "status"
+	AuditActionImport    = "import"
+	AuditActionBudget    = "budget"
+	// AuditActionCalendarToken is recorded without a diff, the token itself is never stored
+	AuditActionCalendarToken = "calendar_token"
+)
+
+type AuditRecord struct {
+	ID        int             `json:"id" db:"id"`
+	Actor     string          `json:"actor" db:"actor"`
+	Entity    string          `json:"entity" db:"entity"`
+	EntityID  int             `json:"entity_id" db:"entity_id"`
+	Action    string          `json:"action" db:"action"`
+	Diff      json.RawMessage `json:"diff" db:"diff" swaggertype:"object"` // field -> {before, after}
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	BudgetKindHours = "hours"
+	BudgetKindMoney = "money"
+
+	BudgetPeriodLifetime = "lifetime"
+	BudgetPeriodWeek     = "week"
+	BudgetPeriodMonth    = "month"
+	BudgetPeriodQuarter  = "quarter"
+	BudgetPeriodYear     = "year"
+)
+
+// ProjectBudget limits the time of the completed tasks of a project within every period or over its lifetime.
+// Limit is in minutes for hours budgets and in minor currency units for money budgets, whose time is billed at Rate
+// per hour.
+type ProjectBudget struct {
+	ProjectID int        `json:"project_id" db:"project_id"`
+	Kind      string     `json:"kind" db:"kind"`
+	Period    string     `json:"period" db:"period"`
+	Limit     int64      `json:"limit" db:"limit"`
+	Rate      int64      `json:"rate,omitempty" db:"rate"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BudgetAlert is raised once per period for every threshold, in percent, the consumption of a budget crossed.
+type BudgetAlert struct {
+	ID         int        `json:"id" db:"id"`
+	ProjectID  int        `json:"project_id" db:"project_id"`
+	PeriodKey  string     `json:"period_key" db:"period_key"` // e.g. 2026-10, 2026-W42, 2026-Q4, 2026 or lifetime
+	Threshold  int        `json:"threshold" db:"threshold"`
+	Consumed   int64      `json:"consumed" db:"consumed"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	NotifiedAt *time.Time `json:"notified_at" db:"notified_at"`
+}
+
+// BudgetBurndown is the consumption of a budget within a period day by day, amounts are in the units of the
+// budget limit. The period of lifetime budgets starts at the first task of the project.
+type BudgetBurndown struct {
+	Budget      ProjectBudget   `json:"budget"`
+	PeriodKey   string          `json:"period_key"`
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"` // exclusive
+	Consumed    int64           `json:"consumed"`
+	Remaining   int64           `json:"remaining"` // negative when over budget
+	Points      []BurndownPoint `json:"points"`
+}
+
+type BurndownPoint struct {
+	Date      time.Time `json:"date"`
+	Consumed  int64     `json:"consumed"`        // up to the end of the day
+	Remaining int64     `json:"remaining"`       // of the limit at the end of the day
+	Ideal     *int64    `json:"ideal,omitempty"` // remaining at an even pace through the period, nil for lifetime budgets
+}
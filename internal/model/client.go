@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// Client is a customer the time of its projects is billed to.
+type Client struct {
+	ID          int        `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	ContactName string     `json:"contact_name" db:"contact_name"`
+	Email       string     `json:"email" db:"email"`
+	Phone       string     `json:"phone" db:"phone"`
+	Address     string     `json:"address" db:"address"`
+	Currency    string     `json:"currency" db:"currency"`
+	DefaultRate int64      `json:"default_rate" db:"default_rate"` // per hour in minor currency units, zero for none
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at" db:"updated_at"`
+}
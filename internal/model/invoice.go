@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	// InvoiceGroupByTask bills every task on its own line.
+	InvoiceGroupByTask = "task"
+	// InvoiceGroupByProject bills the tasks of a project at the same rate on one line. Tasks have no tags, so
+	// the project stands in for the grouping by tag the invoices were asked for.
+	InvoiceGroupByProject = "project"
+)
+
+// Invoice amounts are in minor currency units, e.g. kopecks, rates are per hour.
+type Invoice struct {
+	ID         int           `json:"id" db:"id"`
+	Number     string        `json:"number" db:"number"`
+	ProjectID  *int          `json:"project_id,omitempty" db:"project_id"`
+	ClientID   *int          `json:"client_id,omitempty" db:"client_id"`
+	Issuer     string        `json:"issuer" db:"issuer"`
+	Customer   string        `json:"customer" db:"customer"`
+	Currency   string        `json:"currency" db:"currency"`
+	PeriodFrom time.Time     `json:"period_from" db:"period_from"`
+	PeriodTo   time.Time     `json:"period_to" db:"period_to"`
+	GroupBy    string        `json:"group_by" db:"group_by"`
+	Subtotal   int64         `json:"subtotal" db:"subtotal"`
+	TaxRate    float64       `json:"tax_rate" db:"tax_rate"` // percent
+	Tax        int64         `json:"tax" db:"tax"`
+	Total      int64         `json:"total" db:"total"`
+	Notes      string        `json:"notes,omitempty" db:"notes"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	Lines      []InvoiceLine `json:"lines" db:"-"`
+	TaskIDs    []int         `json:"task_ids" db:"-"` // the billed tasks
+}
+
+type InvoiceLine struct {
+	Description string `json:"description" db:"description"`
+	Duration    int    `json:"duration" db:"duration"` // in minutes
+	Rate        int64  `json:"rate" db:"rate"`
+	Amount      int64  `json:"amount" db:"amount"`
+}
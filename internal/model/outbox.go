@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventTaskCreated   = "task.created"
+	EventTaskCompleted = "task.completed"
+	EventUserCreated   = "user.created"
+	EventUserDeleted   = "user.deleted"
+)
+
+const (
+	AggregateTask = "task"
+	AggregateUser = "user"
+)
+
+// OutboxEvent is a domain event written in the transaction of the change it describes, the relay publishes
+// the events of an aggregate in the order of their IDs.
+type OutboxEvent struct {
+	ID          int64           `json:"id" db:"id"`
+	Aggregate   string          `json:"aggregate" db:"aggregate"`
+	AggregateID int             `json:"aggregate_id" db:"aggregate_id"`
+	Event       string          `json:"event" db:"event"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	// NextAttemptAt is nil once the event is published
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	PublishedAt   *time.Time `json:"published_at,omitempty" db:"published_at"`
+}
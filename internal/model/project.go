@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+type Project struct {
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	ClientID  *int       `json:"client_id,omitempty" db:"client_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// WorklogEntry is the time a user tracked in the reported period.
+type WorklogEntry struct {
+	UserID     int    `json:"user_id" db:"user_id"`
+	Name       string `json:"name" db:"username"`
+	Surname    string `json:"surname" db:"surname"`
+	Patronymic string `json:"patronymic" db:"patronymic"`
+	Status     string `json:"status" db:"status"`
+	Tasks      int    `json:"tasks" db:"tasks"`
+	Duration   int    `json:"duration" db:"duration"` // in minutes
+}
+
+// ClientWorklogEntry is the time tracked for the projects of a client in the reported period, ClientID is nil
+// for the time of tasks without a project or of projects without a client.
+type ClientWorklogEntry struct {
+	ClientID         *int   `json:"client_id" db:"client_id"`
+	Name             string `json:"name" db:"name"`
+	Projects         int    `json:"projects" db:"projects"`
+	Tasks            int    `json:"tasks" db:"tasks"`
+	Duration         int    `json:"duration" db:"duration"`                   // in minutes
+	BillableDuration int    `json:"billable_duration" db:"billable_duration"` // in minutes
+}
@@ -7,9 +7,15 @@ import (
 type Task struct {
 	ID          int        `json:"id" db:"id"`
 	UserID      int        `json:"user_id" db:"user_id"`
+	ProjectID   *int       `json:"project_id,omitempty" db:"project_id"`
 	Description string     `json:"description" db:"description"`
 	Duration    int        `json:"duration" db:"duration"` // in minutes
 	Completed   bool       `json:"completed" db:"completed"`
+	Billable    bool       `json:"billable" db:"billable"`
+	InvoiceID   *int       `json:"invoice_id,omitempty" db:"invoice_id"` // set once the task is billed
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// ExternalID identifies a time entry imported from another tracker, see TrackerImport
+	ExternalID *string `json:"external_id,omitempty" db:"external_id"`
 }
@@ -4,14 +4,76 @@ import (
 	"time"
 )
 
+const (
+	EnrichmentStatusEnriched = "enriched"
+	// EnrichmentStatusPending means the People info API was unavailable on creation, a background job retries it.
+	EnrichmentStatusPending = "pending_enrichment"
+	// EnrichmentStatusFailed means the People info API rejected the passport number.
+	EnrichmentStatusFailed = "enrichment_failed"
+	// EnrichmentStatusManual means the profile was given on creation, e.g. for documents without an external lookup.
+	EnrichmentStatusManual = "manual"
+)
+
+const (
+	UserStatusActive = "active"
+	// UserStatusSuspended users can not get new tasks until they are active again.
+	UserStatusSuspended = "suspended"
+	// UserStatusTerminated users are former employees, they are hidden from user lists but their tasks stay in reports.
+	UserStatusTerminated = "terminated"
+)
+
 type User struct {
-	ID             int    `json:"id" db:"id"`
-	Name           string `json:"name" db:"username"`
-	Surname        string `json:"surname" db:"surname"`
-	Patronymic     string `json:"patronymic" db:"patronymic"`
-	PassportNumber string `json:"passport_number" db:"passport_number"`
-	Address        string `json:"address" db:"address"`
+	ID              int    `json:"id" db:"id"`
+	Name            string `json:"name" db:"username"`
+	Surname         string `json:"surname" db:"surname"`
+	Patronymic      string `json:"patronymic" db:"patronymic"`
+	PassportNumber  string `json:"passport_number" db:"passport_number"` // number of the identity document
+	DocumentType    string `json:"document_type" db:"document_type"`
+	DocumentCountry string `json:"document_country" db:"document_country"` // ISO 3166-1 alpha-2
+	Address         string `json:"address" db:"address"`
+	Email           string `json:"email,omitempty" db:"email"`
+
+	// Status is the lifecycle status in effect now, see UserStatusChange
+	Status string `json:"status" db:"status"`
+
+	EnrichmentStatus string `json:"enrichment_status" db:"enrichment_status"`
+	// ProfilePinned opts the user out of the periodic re-synchronisation with the People info API
+	ProfilePinned bool       `json:"profile_pinned" db:"profile_pinned"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty" db:"synced_at"`
 
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty" db:"anonymized_at"`
+	// MergedIntoID is the user that took over the tasks of this duplicate
+	MergedIntoID *int `json:"merged_into_id,omitempty" db:"merged_into_id"`
+}
+
+// UserStatusChange moves the user to Status from EffectiveFrom until the next change.
+type UserStatusChange struct {
+	ID            int       `json:"id" db:"id"`
+	UserID        int       `json:"user_id" db:"user_id"`
+	Status        string    `json:"status" db:"status"`
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"`
+	Reason        string    `json:"reason" db:"reason"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// DuplicateCandidate is a user that may be the same person, scores are trigram similarities from 0 to 1.
+type DuplicateCandidate struct {
+	User  User    `json:"user"`
+	Score float64 `json:"score"` // weighted sum of the field scores
+
+	NameScore       float64 `json:"name_score"`
+	SurnameScore    float64 `json:"surname_score"`
+	PatronymicScore float64 `json:"patronymic_score"`
+	AddressScore    float64 `json:"address_score"`
+}
+
+// UserExport bundles everything stored about a user for a data subject access request.
+type UserExport struct {
+	User         User          `json:"user"`
+	Tasks        []Task        `json:"tasks"`
+	AuditRecords []AuditRecord `json:"audit_records"`
 }
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryFailed deliveries ran out of attempts, they can be redelivered by hand.
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookSubscription receives the events listed in Events, all of them when it is empty. Payloads are
+// signed with Secret, which is only shown when the subscription is created.
+type WebhookSubscription struct {
+	ID        int       `json:"id" db:"id"`
+	URL       string    `json:"url" db:"url"`
+	Events    []string  `json:"events" db:"events"`
+	Secret    string    `json:"secret,omitempty" db:"secret"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookEvent is the JSON payload of webhook deliveries, redeliveries repeat the event ID.
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type WebhookDelivery struct {
+	ID             int             `json:"id" db:"id"`
+	SubscriptionID int             `json:"subscription_id" db:"subscription_id"`
+	EventID        string          `json:"event_id" db:"event_id"`
+	Event          string          `json:"event" db:"event"`
+	Payload        json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"` // of the last attempt
+	LastError      string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
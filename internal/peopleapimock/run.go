@@ -0,0 +1,54 @@
+package peopleapimock
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"time-tracker/pkg/httpserver"
+
+	"github.com/caarlos0/env/v6"
+	log "github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	Port          string        `env:"MOCK_HTTP_PORT" envDefault:"3000"`
+	FixturesPath  string        `env:"MOCK_FIXTURES_PATH" envDefault:"fixtures/people.json"`
+	FixturesOnly  bool          `env:"MOCK_FIXTURES_ONLY"` // unknown passports get 400 instead of a fake person
+	Latency       time.Duration `env:"MOCK_LATENCY"`
+	LatencyJitter time.Duration `env:"MOCK_LATENCY_JITTER"`
+	ErrorRate     float64       `env:"MOCK_ERROR_RATE"` // 0..1 share of requests answered with ErrorStatus
+	ErrorStatus   int           `env:"MOCK_ERROR_STATUS" envDefault:"500"`
+}
+
+func Run() {
+	cfg := Config{}
+	if err := env.Parse(&cfg); err != nil {
+		log.Fatalf("error init config: %v", err)
+	}
+
+	fixtures, err := loadFixtures(cfg.FixturesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("People info mock: %d fixtures loaded from %s", len(fixtures), cfg.FixturesPath)
+
+	s := &server{cfg: cfg, fixtures: fixtures}
+	httpServer := httpserver.New(s.routes(), httpserver.Port(cfg.Port))
+	log.Infof("People info mock: listening on :%s", cfg.Port)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case sig := <-interrupt:
+		log.Info("peopleapimock - Run - signal: " + sig.String())
+	case err := <-httpServer.Notify():
+		log.Error(fmt.Errorf("peopleapimock - Run - httpServer.Notify: %w", err))
+	}
+
+	if err := httpServer.Shutdown(); err != nil {
+		log.Error(fmt.Errorf("peopleapimock - Run - httpServer.Shutdown: %w", err))
+	}
+}
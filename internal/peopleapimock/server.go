@@ -0,0 +1,118 @@
+// Package peopleapimock is a local stand-in for the external People info API,
+// it serves fixtures and deterministic fake people with optional latency and errors.
+package peopleapimock
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+)
+
+var (
+	seriePattern  = regexp.MustCompile(`^\d{4}$`)
+	numberPattern = regexp.MustCompile(`^\d{6}$`)
+
+	fakeSurnames    = []string{"Ivanov", "Petrov", "Sidorov", "Smirnov", "Kuznetsov", "Popov", "Vasiliev", "Sokolov"}
+	fakeNames       = []string{"Ivan", "Petr", "Sergey", "Alexey", "Dmitry", "Nikolay", "Andrey", "Mikhail"}
+	fakePatronymics = []string{"Ivanovich", "Petrovich", "Sergeevich", "Alexeevich", "Dmitrievich", "Nikolaevich"}
+	fakeCities      = []string{"Moscow", "Saint Petersburg", "Kazan", "Novosibirsk", "Yekaterinburg", "Samara"}
+	fakeStreets     = []string{"Lenina st.", "Mira pr.", "Sadovaya st.", "Pushkina st.", "Gagarina st."}
+)
+
+type person struct {
+	Surname    string `json:"surname"`
+	Name       string `json:"name"`
+	Patronymic string `json:"patronymic"`
+	Address    string `json:"address"`
+}
+
+type server struct {
+	cfg      Config
+	fixtures map[string]person
+}
+
+func loadFixtures(path string) (map[string]person, error) {
+	fixtures := make(map[string]person)
+	if path == "" {
+		return fixtures, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("peopleapimock - loadFixtures - os.ReadFile: %w", err)
+	}
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("peopleapimock - loadFixtures - json.Unmarshal: %w", err)
+	}
+	return fixtures, nil
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", s.info)
+	return mux
+}
+
+// info implements GET /info?passportSerie=1234&passportNumber=567890.
+func (s *server) info(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.cfg.Latency > 0 || s.cfg.LatencyJitter > 0 {
+		delay := s.cfg.Latency
+		if s.cfg.LatencyJitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(s.cfg.LatencyJitter)))
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	if s.cfg.ErrorRate > 0 && rand.Float64() < s.cfg.ErrorRate {
+		w.WriteHeader(s.cfg.ErrorStatus)
+		return
+	}
+
+	serie, number := r.URL.Query().Get("passportSerie"), r.URL.Query().Get("passportNumber")
+	if !seriePattern.MatchString(serie) || !numberPattern.MatchString(number) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	p, ok := s.fixtures[serie+" "+number]
+	if !ok {
+		if s.cfg.FixturesOnly {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		p = fakePerson(serie + number)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// fakePerson always returns the same person for the same passport.
+func fakePerson(passport string) person {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(passport))
+	rnd := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	return person{
+		Surname:    fakeSurnames[rnd.Intn(len(fakeSurnames))],
+		Name:       fakeNames[rnd.Intn(len(fakeNames))],
+		Patronymic: fakePatronymics[rnd.Intn(len(fakePatronymics))],
+		Address: fmt.Sprintf("%s, %s %d, apt. %d",
+			fakeCities[rnd.Intn(len(fakeCities))], fakeStreets[rnd.Intn(len(fakeStreets))], rnd.Intn(100)+1, rnd.Intn(300)+1,
+		),
+	}
+}
@@ -0,0 +1,138 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/pkg/postgres"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type AuditRepo struct {
+	*postgres.Postgres
+	maxPageLimit int
+}
+
+func NewAuditRepo(db *postgres.Postgres, maxPageLimit int) *AuditRepo {
+	return &AuditRepo{db, maxPageLimit}
+}
+
+type CreateAuditRecordInput struct {
+	Actor    string
+	Entity   string
+	EntityID int
+	Action   string
+	Diff     []byte
+}
+
+func (r *AuditRepo) CreateRecord(ctx context.Context, data CreateAuditRecordInput) (int, error) {
+	var ID int
+	sql, args, _ := r.Builder.Insert("md.audit_log").
+		Columns("actor", "entity", "entity_id", "action", "diff", "created_at").
+		Values(data.Actor, data.Entity, data.EntityID, data.Action, string(data.Diff), time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		return 0, fmt.Errorf("AuditRepo.CreateRecord - r.Conn.QueryRow: %v", err)
+	}
+	return ID, nil
+}
+
+type ListAuditRecordsFilter struct {
+	Entity   string
+	EntityID int
+	Limit    int
+	Offset   int
+}
+
+func (r *AuditRepo) ListRecords(ctx context.Context, filter ListAuditRecordsFilter) ([]model.AuditRecord, error) {
+	limit := pageLimit(filter.Limit, r.maxPageLimit)
+
+	sql, args, _ := r.Builder.Select("id", "actor", "entity", "entity_id", "action", "diff", "created_at").
+		From("md.audit_log").
+		Where("entity = ? AND entity_id = ?", filter.Entity, filter.EntityID).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("AuditRepo.ListRecords - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var records []model.AuditRecord
+	for rows.Next() {
+		var record model.AuditRecord
+		if err := rows.Scan(
+			&record.ID, &record.Actor, &record.Entity, &record.EntityID, &record.Action, &record.Diff, &record.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("AuditRepo.ListRecords - rows.Scan: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// ListEntityRecords returns the whole history of the given entities, oldest first.
+func (r *AuditRepo) ListEntityRecords(ctx context.Context, entity string, entityIDs []int) ([]model.AuditRecord, error) {
+	sql, args, _ := r.Builder.Select("id", "actor", "entity", "entity_id", "action", "diff", "created_at").
+		From("md.audit_log").
+		Where(squirrel.Eq{"entity": entity, "entity_id": entityIDs}).
+		OrderBy("id").
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("AuditRepo.ListEntityRecords - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var records []model.AuditRecord
+	for rows.Next() {
+		var record model.AuditRecord
+		if err := rows.Scan(
+			&record.ID, &record.Actor, &record.Entity, &record.EntityID, &record.Action, &record.Diff, &record.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("AuditRepo.ListEntityRecords - rows.Scan: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// ScrubRecords replaces every value in the diffs of an entity with replacement, keeping the changed field names.
+// The append-only trigger lets this single statement through via the md.audit_scrub setting.
+func (r *AuditRepo) ScrubRecords(ctx context.Context, entity string, entityID int, replacement string) error {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("AuditRepo.ScrubRecords - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, "SET LOCAL md.audit_scrub = 'on'")
+	if err != nil {
+		return fmt.Errorf("AuditRepo.ScrubRecords - tx.Exec: %v", err)
+	}
+
+	sql, args, _ := r.Builder.Update("md.audit_log").
+		Set("diff", squirrel.Expr(
+			"COALESCE((SELECT jsonb_object_agg(key, jsonb_build_object('before', ?::text, 'after', ?::text)) FROM jsonb_object_keys(diff) AS key), '{}'::jsonb)",
+			replacement, replacement,
+		)).
+		Where("entity = ? AND entity_id = ?", entity, entityID).
+		ToSql()
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AuditRepo.ScrubRecords - tx.Exec: %v", err)
+	}
+	return tx.Commit(ctx)
+}
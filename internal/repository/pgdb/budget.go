@@ -0,0 +1,229 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	budgetColumns      = []string{"project_id", "kind", "period", `"limit"`, "rate", "created_at", "updated_at"}
+	budgetAlertColumns = []string{"id", "project_id", "period_key", "threshold", "consumed", "created_at", "notified_at"}
+)
+
+type BudgetRepo struct {
+	*postgres.Postgres
+}
+
+func NewBudgetRepo(db *postgres.Postgres) *BudgetRepo {
+	return &BudgetRepo{db}
+}
+
+type SetBudgetInput struct {
+	ProjectID int
+	Kind      string
+	Period    string
+	Limit     int64
+	Rate      int64
+}
+
+// SetBudget creates or replaces the budget of the project, the alerts of the replaced budget are dropped so that
+// the new limit alerts again.
+func (r *BudgetRepo) SetBudget(ctx context.Context, data SetBudgetInput) error {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("BudgetRepo.SetBudget - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	now := time.Now()
+	sql, args, _ := r.Builder.Insert("md.project_budgets").
+		Columns("project_id", "kind", "period", `"limit"`, "rate", "created_at").
+		Values(data.ProjectID, data.Kind, data.Period, data.Limit, data.Rate, now).
+		Suffix(`ON CONFLICT (project_id) DO UPDATE SET kind = EXCLUDED.kind, "period" = EXCLUDED."period", `+
+			`"limit" = EXCLUDED."limit", rate = EXCLUDED.rate, updated_at = ?`, now).
+		ToSql()
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("BudgetRepo.SetBudget - tx.Exec: %v", err)
+	}
+
+	sql, args, _ = r.Builder.Delete("md.project_budget_alerts").Where("project_id = ?", data.ProjectID).ToSql()
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("BudgetRepo.SetBudget - alerts - tx.Exec: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("BudgetRepo.SetBudget - tx.Commit: %v", err)
+	}
+	return nil
+}
+
+func (r *BudgetRepo) GetBudget(ctx context.Context, projectID int) (model.ProjectBudget, error) {
+	sql, args, _ := r.Builder.Select(budgetColumns...).From("md.project_budgets").Where("project_id = ?", projectID).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return model.ProjectBudget{}, fmt.Errorf("BudgetRepo.GetBudget - r.Conn.Query: %v", err)
+	}
+	budget, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[model.ProjectBudget])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return budget, repoerr.ErrNotFound
+		}
+		return budget, fmt.Errorf("BudgetRepo.GetBudget - pgx.CollectOneRow: %v", err)
+	}
+	return budget, nil
+}
+
+func (r *BudgetRepo) DeleteBudget(ctx context.Context, projectID int) error {
+	sql, args, _ := r.Builder.Delete("md.project_budgets").Where("project_id = ?", projectID).ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("BudgetRepo.DeleteBudget - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
+
+// projectTasks selects the completed tasks of the project created within the period, zero bounds leave it open.
+func projectTasks(projectID int, from, to time.Time) squirrel.And {
+	where := squirrel.And{squirrel.Eq{"project_id": projectID, "completed": true, "deleted_at": nil}}
+	if !from.IsZero() {
+		where = append(where, squirrel.GtOrEq{"created_at": from})
+	}
+	if !to.IsZero() {
+		where = append(where, squirrel.Lt{"created_at": to})
+	}
+	return where
+}
+
+// ProjectDuration sums up the minutes of the completed tasks of the project created in [from, to).
+func (r *BudgetRepo) ProjectDuration(ctx context.Context, projectID int, from, to time.Time) (int64, error) {
+	sql, args, _ := r.Builder.Select("COALESCE(sum(duration), 0)").
+		From("md.tasks").
+		Where(projectTasks(projectID, from, to)).
+		ToSql()
+
+	var duration int64
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&duration)
+	if err != nil {
+		return 0, fmt.Errorf("BudgetRepo.ProjectDuration - r.Conn.QueryRow: %v", err)
+	}
+	return duration, nil
+}
+
+// DailyDuration is the time of the tasks created on the day.
+type DailyDuration struct {
+	Date     time.Time // midnight in UTC of the day in the time zone it was reported in
+	Duration int64     // in minutes
+}
+
+// ProjectDailyDurations is ProjectDuration day by day in the named time zone, days without tasks are left out.
+func (r *BudgetRepo) ProjectDailyDurations(ctx context.Context, projectID int, from, to time.Time, timezone string) ([]DailyDuration, error) {
+	sql, args, _ := r.Builder.Select().
+		Column(squirrel.Expr("(created_at AT TIME ZONE ?)::date AS day", timezone)).
+		Column("sum(duration)").
+		From("md.tasks").
+		Where(projectTasks(projectID, from, to)).
+		GroupBy("day").
+		OrderBy("day").
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("BudgetRepo.ProjectDailyDurations - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var days []DailyDuration
+	for rows.Next() {
+		var day DailyDuration
+		if err := rows.Scan(&day.Date, &day.Duration); err != nil {
+			return nil, fmt.Errorf("BudgetRepo.ProjectDailyDurations - rows.Scan: %v", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("BudgetRepo.ProjectDailyDurations - rows.Err: %v", err)
+	}
+	return days, nil
+}
+
+// FirstProjectTaskTime returns when the first completed task of the project was created, ErrNotFound without tasks.
+func (r *BudgetRepo) FirstProjectTaskTime(ctx context.Context, projectID int) (time.Time, error) {
+	sql, args, _ := r.Builder.Select("min(created_at)").
+		From("md.tasks").
+		Where(projectTasks(projectID, time.Time{}, time.Time{})).
+		ToSql()
+
+	var first *time.Time
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&first)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("BudgetRepo.FirstProjectTaskTime - r.Conn.QueryRow: %v", err)
+	}
+	if first == nil {
+		return time.Time{}, repoerr.ErrNotFound
+	}
+	return *first, nil
+}
+
+// CreateBudgetAlert returns ErrAlreadyExists when the threshold was alerted in the period before.
+func (r *BudgetRepo) CreateBudgetAlert(ctx context.Context, alert model.BudgetAlert) (int, error) {
+	sql, args, _ := r.Builder.Insert("md.project_budget_alerts").
+		Columns("project_id", "period_key", "threshold", "consumed", "created_at").
+		Values(alert.ProjectID, alert.PeriodKey, alert.Threshold, alert.Consumed, time.Now()).
+		Suffix("ON CONFLICT (project_id, period_key, threshold) DO NOTHING RETURNING id").
+		ToSql()
+
+	var ID int
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repoerr.ErrAlreadyExists
+		}
+		return 0, fmt.Errorf("BudgetRepo.CreateBudgetAlert - r.Conn.QueryRow: %v", err)
+	}
+	return ID, nil
+}
+
+// ListPendingBudgetAlerts returns the alerts not notified yet, oldest first.
+func (r *BudgetRepo) ListPendingBudgetAlerts(ctx context.Context, limit int) ([]model.BudgetAlert, error) {
+	sql, args, _ := r.Builder.Select(budgetAlertColumns...).
+		From("md.project_budget_alerts").
+		Where("notified_at IS NULL").
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("BudgetRepo.ListPendingBudgetAlerts - r.Conn.Query: %v", err)
+	}
+	alerts, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.BudgetAlert])
+	if err != nil {
+		return nil, fmt.Errorf("BudgetRepo.ListPendingBudgetAlerts - pgx.CollectRows: %v", err)
+	}
+	return alerts, nil
+}
+
+func (r *BudgetRepo) MarkBudgetAlertNotified(ctx context.Context, ID int, notifiedAt time.Time) error {
+	sql, args, _ := r.Builder.Update("md.project_budget_alerts").Set("notified_at", notifiedAt).Where("id = ?", ID).ToSql()
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("BudgetRepo.MarkBudgetAlertNotified - r.Conn.Exec: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var clientColumns = []string{
+	"id", "name", "contact_name", "email", "phone", "address", "currency", "default_rate", "created_at", "updated_at",
+}
+
+type ClientRepo struct {
+	*postgres.Postgres
+}
+
+func NewClientRepo(db *postgres.Postgres) *ClientRepo {
+	return &ClientRepo{db}
+}
+
+type CreateClientInput struct {
+	Name        string
+	ContactName string
+	Email       string
+	Phone       string
+	Address     string
+	Currency    string
+	DefaultRate int64
+}
+
+// CreateClient returns ErrAlreadyExists when a client with the same name in any case exists.
+func (r *ClientRepo) CreateClient(ctx context.Context, data CreateClientInput) (int, error) {
+	var ID int
+	sql, args, _ := r.Builder.Insert("md.clients").
+		Columns("name", "contact_name", "email", "phone", "address", "currency", "default_rate", "created_at").
+		Values(data.Name, data.ContactName, data.Email, data.Phone, data.Address, data.Currency, data.DefaultRate, time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, repoerr.ErrAlreadyExists
+		}
+		return 0, fmt.Errorf("ClientRepo.CreateClient - r.Conn.QueryRow: %v", err)
+	}
+	return ID, nil
+}
+
+func (r *ClientRepo) GetClient(ctx context.Context, ID int) (model.Client, error) {
+	sql, args, _ := r.Builder.Select(clientColumns...).From("md.clients").Where("id = ?", ID).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return model.Client{}, fmt.Errorf("ClientRepo.GetClient - r.Conn.Query: %v", err)
+	}
+	client, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[model.Client])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return client, repoerr.ErrNotFound
+		}
+		return client, fmt.Errorf("ClientRepo.GetClient - pgx.CollectOneRow: %v", err)
+	}
+	return client, nil
+}
+
+func (r *ClientRepo) ListClients(ctx context.Context) ([]model.Client, error) {
+	sql, args, _ := r.Builder.Select(clientColumns...).From("md.clients").OrderBy("name", "id").ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo.ListClients - r.Conn.Query: %v", err)
+	}
+	clients, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.Client])
+	if err != nil {
+		return nil, fmt.Errorf("ClientRepo.ListClients - pgx.CollectRows: %v", err)
+	}
+	return clients, nil
+}
+
+type UpdateClientInput struct {
+	Name        *string
+	ContactName *string
+	Email       *string
+	Phone       *string
+	Address     *string
+	Currency    *string
+	DefaultRate *int64
+}
+
+// UpdateClient returns ErrAlreadyExists when the new name is taken in any case.
+func (r *ClientRepo) UpdateClient(ctx context.Context, ID int, data UpdateClientInput) error {
+	b := r.Builder.Update("md.clients").Set("updated_at", time.Now())
+	if data.Name != nil {
+		b = b.Set("name", *data.Name)
+	}
+	if data.ContactName != nil {
+		b = b.Set("contact_name", *data.ContactName)
+	}
+	if data.Email != nil {
+		b = b.Set("email", *data.Email)
+	}
+	if data.Phone != nil {
+		b = b.Set("phone", *data.Phone)
+	}
+	if data.Address != nil {
+		b = b.Set("address", *data.Address)
+	}
+	if data.Currency != nil {
+		b = b.Set("currency", *data.Currency)
+	}
+	if data.DefaultRate != nil {
+		b = b.Set("default_rate", *data.DefaultRate)
+	}
+	sql, args, _ := b.Where("id = ?", ID).ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repoerr.ErrAlreadyExists
+		}
+		return fmt.Errorf("ClientRepo.UpdateClient - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+var invoiceColumns = []string{
+	"id", "number", "project_id", "client_id", "issuer", "customer", "currency", "period_from", "period_to", "group_by",
+	"subtotal", "tax_rate", "tax", "total", "notes", "created_at",
+}
+
+type InvoiceRepo struct {
+	*postgres.Postgres
+}
+
+func NewInvoiceRepo(db *postgres.Postgres) *InvoiceRepo {
+	return &InvoiceRepo{db}
+}
+
+// BillableTasksFilter selects the tasks created within the period, ProjectID and ClientID nil select tasks of
+// any project and client.
+type BillableTasksFilter struct {
+	DateFrom  time.Time
+	DateTo    time.Time
+	ProjectID *int
+	ClientID  *int
+}
+
+// ListBillableTasks returns the completed billable tasks not invoiced yet in the order they were created.
+func (r *InvoiceRepo) ListBillableTasks(ctx context.Context, filter BillableTasksFilter) ([]model.Task, error) {
+	where := squirrel.And{
+		squirrel.Eq{"completed": true, "billable": true, "invoice_id": nil, "deleted_at": nil},
+		squirrel.GtOrEq{"created_at": filter.DateFrom},
+		squirrel.LtOrEq{"created_at": filter.DateTo},
+	}
+	if filter.ProjectID != nil {
+		where = append(where, squirrel.Eq{"project_id": *filter.ProjectID})
+	}
+	if filter.ClientID != nil {
+		where = append(where, squirrel.Expr("project_id IN (SELECT id FROM md.projects WHERE client_id = ?)", *filter.ClientID))
+	}
+	sql, args, _ := r.Builder.Select(taskColumns...).From("md.tasks").Where(where).OrderBy("created_at", "id").ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("InvoiceRepo.ListBillableTasks - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var tasks []model.Task
+	for rows.Next() {
+		var task model.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, fmt.Errorf("InvoiceRepo.ListBillableTasks - rows.Scan: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("InvoiceRepo.ListBillableTasks - rows.Err: %v", err)
+	}
+	return tasks, nil
+}
+
+// CreateInvoiceInput is an invoice without its number, which CreateInvoice assigns.
+type CreateInvoiceInput struct {
+	NumberPrefix string
+	model.Invoice
+}
+
+// CreateInvoice numbers the invoice sequentially within the year without gaps and marks its tasks invoiced,
+// all in one transaction. ErrAlreadyExists is returned when a task was invoiced since it was listed.
+func (r *InvoiceRepo) CreateInvoice(ctx context.Context, data CreateInvoiceInput) (int, error) {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("InvoiceRepo.CreateInvoice - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// the counter row stays locked until commit, so concurrent invoices are numbered one after another
+	now := time.Now()
+	sql, args, _ := r.Builder.Insert("md.invoice_counters").
+		Columns("year", "last_number").
+		Values(now.Year(), 1).
+		Suffix("ON CONFLICT (year) DO UPDATE SET last_number = md.invoice_counters.last_number + 1 RETURNING last_number").
+		ToSql()
+	var seq int
+	err = tx.QueryRow(ctx, sql, args...).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("InvoiceRepo.CreateInvoice - counter - tx.QueryRow: %v", err)
+	}
+
+	sql, args, _ = r.Builder.Insert("md.invoices").
+		Columns("number", "project_id", "client_id", "issuer", "customer", "currency", "period_from", "period_to", "group_by",
+			"subtotal", "tax_rate", "tax", "total", "notes", "created_at").
+		Values(fmt.Sprintf("%s%d-%05d", data.NumberPrefix, now.Year(), seq), data.ProjectID, data.ClientID, data.Issuer, data.Customer,
+			data.Currency, data.PeriodFrom, data.PeriodTo, data.GroupBy,
+			data.Subtotal, data.TaxRate, data.Tax, data.Total, data.Notes, now).
+		Suffix("RETURNING id").
+		ToSql()
+	var ID int
+	err = tx.QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		return 0, fmt.Errorf("InvoiceRepo.CreateInvoice - tx.QueryRow: %v", err)
+	}
+
+	lines := r.Builder.Insert("md.invoice_lines").Columns("invoice_id", "position", "description", "duration", "rate", "amount")
+	for i, line := range data.Lines {
+		lines = lines.Values(ID, i+1, line.Description, line.Duration, line.Rate, line.Amount)
+	}
+	sql, args, _ = lines.ToSql()
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("InvoiceRepo.CreateInvoice - lines - tx.Exec: %v", err)
+	}
+
+	sql, args, _ = r.Builder.Update("md.tasks").
+		Set("invoice_id", ID).
+		Where("id = ANY(?) AND invoice_id IS NULL", data.TaskIDs).
+		ToSql()
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("InvoiceRepo.CreateInvoice - tasks - tx.Exec: %v", err)
+	}
+	if tag.RowsAffected() != int64(len(data.TaskIDs)) {
+		return 0, repoerr.ErrAlreadyExists
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("InvoiceRepo.CreateInvoice - tx.Commit: %v", err)
+	}
+	return ID, nil
+}
+
+// GetInvoice returns the invoice with its lines and billed tasks.
+func (r *InvoiceRepo) GetInvoice(ctx context.Context, ID int) (model.Invoice, error) {
+	sql, args, _ := r.Builder.Select(invoiceColumns...).From("md.invoices").Where("id = ?", ID).ToSql()
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return model.Invoice{}, fmt.Errorf("InvoiceRepo.GetInvoice - r.Conn.Query: %v", err)
+	}
+	invoice, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[model.Invoice])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return invoice, repoerr.ErrNotFound
+		}
+		return invoice, fmt.Errorf("InvoiceRepo.GetInvoice - pgx.CollectOneRow: %v", err)
+	}
+
+	sql, args, _ = r.Builder.Select("description", "duration", "rate", "amount").
+		From("md.invoice_lines").
+		Where("invoice_id = ?", ID).
+		OrderBy("position").
+		ToSql()
+	rows, err = r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return invoice, fmt.Errorf("InvoiceRepo.GetInvoice - lines - r.Conn.Query: %v", err)
+	}
+	invoice.Lines, err = pgx.CollectRows(rows, pgx.RowToStructByName[model.InvoiceLine])
+	if err != nil {
+		return invoice, fmt.Errorf("InvoiceRepo.GetInvoice - lines - pgx.CollectRows: %v", err)
+	}
+
+	sql, args, _ = r.Builder.Select("id").From("md.tasks").Where("invoice_id = ?", ID).OrderBy("id").ToSql()
+	rows, err = r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return invoice, fmt.Errorf("InvoiceRepo.GetInvoice - tasks - r.Conn.Query: %v", err)
+	}
+	invoice.TaskIDs, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return invoice, fmt.Errorf("InvoiceRepo.GetInvoice - tasks - pgx.CollectRows: %v", err)
+	}
+	return invoice, nil
+}
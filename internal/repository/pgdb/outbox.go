@@ -0,0 +1,138 @@
+package pgdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/pkg/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+var outboxColumns = []string{
+	"id", "aggregate", "aggregate_id", "event", "payload", "attempts", "next_attempt_at", "last_error",
+	"created_at", "published_at",
+}
+
+type OutboxRepo struct {
+	*postgres.Postgres
+}
+
+func NewOutboxRepo(db *postgres.Postgres) *OutboxRepo {
+	return &OutboxRepo{db}
+}
+
+// addOutboxEvent writes the event within tx, so that it is published if and only if the change it describes
+// is committed. The change locks the aggregate row first, which keeps the IDs of its events in commit order.
+func addOutboxEvent(ctx context.Context, tx pgx.Tx, b squirrel.StatementBuilderType, aggregate string, aggregateID int, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	sql, args, _ := b.Insert("md.outbox").
+		Columns("aggregate", "aggregate_id", "event", "payload", "created_at").
+		Values(aggregate, aggregateID, event, payload, time.Now()).
+		ToSql()
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("tx.Exec: %v", err)
+	}
+	return nil
+}
+
+// ClaimOutboxEvents returns the unpublished events due by now in the order of their IDs and postpones them by
+// lease, an event interrupted by a crash is published again once the lease expires. Events queued behind an
+// earlier event of the same aggregate that is not due, i.e. claimed elsewhere or waiting for a retry, are left
+// out so that every aggregate is published in order.
+func (r *OutboxRepo) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("OutboxRepo.ClaimOutboxEvents - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// claims are serialized, otherwise a concurrent claim would not see the events leased by this one
+	_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('md.outbox'))")
+	if err != nil {
+		return nil, fmt.Errorf("OutboxRepo.ClaimOutboxEvents - tx.Exec: %v", err)
+	}
+
+	now := time.Now()
+	// the outer statement numbers the placeholders of the subqueries
+	blocked, blockedArgs, _ := squirrel.Select("1").
+		From("md.outbox p").
+		Where("p.aggregate = o.aggregate AND p.aggregate_id = o.aggregate_id AND p.id < o.id").
+		Where("p.published_at IS NULL AND p.next_attempt_at > ?", now).
+		ToSql()
+	due, dueArgs, _ := squirrel.Select("o.id").
+		From("md.outbox o").
+		Where("o.published_at IS NULL AND o.next_attempt_at <= ?", now).
+		Where("NOT EXISTS ("+blocked+")", blockedArgs...).
+		OrderBy("o.id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	sql, args, _ := r.Builder.Update("md.outbox").
+		Set("next_attempt_at", now.Add(lease)).
+		Where("id IN ("+due+")", dueArgs...).
+		Suffix("RETURNING " + strings.Join(outboxColumns, ", ")).
+		ToSql()
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("OutboxRepo.ClaimOutboxEvents - tx.Query: %v", err)
+	}
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.OutboxEvent])
+	if err != nil {
+		return nil, fmt.Errorf("OutboxRepo.ClaimOutboxEvents - pgx.CollectRows: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("OutboxRepo.ClaimOutboxEvents - tx.Commit: %v", err)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+// UpdateOutboxEventInput records an attempt, a published event has PublishedAt and no NextAttemptAt.
+type UpdateOutboxEventInput struct {
+	Attempts      int
+	NextAttemptAt *time.Time
+	LastError     string
+	PublishedAt   *time.Time
+}
+
+func (r *OutboxRepo) UpdateOutboxEvent(ctx context.Context, ID int64, data UpdateOutboxEventInput) error {
+	sql, args, _ := r.Builder.Update("md.outbox").
+		Set("attempts", data.Attempts).
+		Set("next_attempt_at", data.NextAttemptAt).
+		Set("last_error", data.LastError).
+		Set("published_at", data.PublishedAt).
+		Where("id = ?", ID).
+		ToSql()
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("OutboxRepo.UpdateOutboxEvent - r.Conn.Exec: %v", err)
+	}
+	return nil
+}
+
+// PurgeOutboxEvents removes the events published before the given time and returns their number.
+func (r *OutboxRepo) PurgeOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	sql, args, _ := r.Builder.Delete("md.outbox").
+		Where("published_at < ?", publishedBefore).
+		ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("OutboxRepo.PurgeOutboxEvents - r.Conn.Exec: %v", err)
+	}
+	return tag.RowsAffected(), nil
+}
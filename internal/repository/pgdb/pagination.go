@@ -0,0 +1,209 @@
+package pgdb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time-tracker/internal/repository/repoerr"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const defaultPaginationLimit = 10
+
+// PageRequest is the pagination contract of list methods. Sort is a comma separated list of fields,
+// a leading '-' sorts the field descending, e.g. "surname,-created_at". Cursor is the NextCursor
+// of the previous page, it is only valid with the same Sort.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	Sort      string
+	WithTotal bool
+}
+
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // empty on the last page
+	Total      int    // counted only when WithTotal was requested
+}
+
+// sortColumn is a field a list can be sorted by, value reads it from a row to build the next cursor.
+type sortColumn[T any] struct {
+	column string
+	value  func(T) any
+}
+
+type sortField[T any] struct {
+	sortColumn[T]
+	desc bool
+}
+
+type cursor struct {
+	Sort   string            `json:"s,omitempty"`
+	Values []json.RawMessage `json:"v,omitempty"`
+	Offset int               `json:"o,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil {
+		return c, repoerr.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func pageLimit(limit, maxLimit int) int {
+	if limit <= 0 {
+		limit = defaultPaginationLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return limit
+}
+
+// pager pages a query either by keyset over the sort fields with id as the tie breaker,
+// or by offset for queries with their own order such as relevance ranked search.
+type pager[T any] struct {
+	limit int
+
+	sort   string
+	fields []sortField[T]
+	after  []any
+
+	byOffset bool
+	offset   int
+}
+
+// newKeysetPager requires columns to contain "id".
+func newKeysetPager[T any](page PageRequest, maxLimit int, columns map[string]sortColumn[T], defaultSort string) (*pager[T], error) {
+	p := &pager[T]{limit: pageLimit(page.Limit, maxLimit), sort: page.Sort}
+	if p.sort == "" {
+		p.sort = defaultSort
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(p.sort, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		column, ok := columns[name]
+		if !ok || seen[name] {
+			return nil, fmt.Errorf("%w: %q", repoerr.ErrInvalidSort, name)
+		}
+		seen[name] = true
+		p.fields = append(p.fields, sortField[T]{column, desc})
+	}
+	if !seen["id"] {
+		p.fields = append(p.fields, sortField[T]{columns["id"], false})
+	}
+
+	if page.Cursor == "" {
+		return p, nil
+	}
+	c, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if c.Sort != p.sort || len(c.Values) != len(p.fields) {
+		return nil, repoerr.ErrInvalidCursor
+	}
+	var zero T
+	for i, field := range p.fields {
+		// decode into the type the column value has, so that times and numbers reach postgres typed
+		value := reflect.New(reflect.TypeOf(field.value(zero)))
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			return nil, repoerr.ErrInvalidCursor
+		}
+		p.after = append(p.after, value.Elem().Interface())
+	}
+	return p, nil
+}
+
+func newOffsetPager[T any](page PageRequest, maxLimit int) (*pager[T], error) {
+	p := &pager[T]{limit: pageLimit(page.Limit, maxLimit), byOffset: true}
+	if page.Cursor == "" {
+		return p, nil
+	}
+	c, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if c.Offset < 0 || c.Values != nil {
+		return nil, repoerr.ErrInvalidCursor
+	}
+	p.offset = c.Offset
+	return p, nil
+}
+
+// apply fetches one row more than the limit, page uses it to tell whether a next page exists.
+func (p *pager[T]) apply(query squirrel.SelectBuilder) squirrel.SelectBuilder {
+	query = query.Limit(uint64(p.limit + 1))
+	if p.byOffset {
+		return query.Offset(uint64(p.offset))
+	}
+
+	query = p.order(query)
+	if p.after == nil {
+		return query
+	}
+
+	// (a, b, id) after (x, y, z) is a > x OR (a = x AND b > y) OR (a = x AND b = y AND id > z) with the
+	// comparison flipped for descending fields
+	after := squirrel.Or{}
+	for i, field := range p.fields {
+		clause := squirrel.And{}
+		for j := 0; j < i; j++ {
+			clause = append(clause, squirrel.Eq{p.fields[j].column: p.after[j]})
+		}
+		if field.desc {
+			clause = append(clause, squirrel.Lt{field.column: p.after[i]})
+		} else {
+			clause = append(clause, squirrel.Gt{field.column: p.after[i]})
+		}
+		after = append(after, clause)
+	}
+	return query.Where(after)
+}
+
+// order sorts by the sort fields without paging, e.g. for streamed exports.
+func (p *pager[T]) order(query squirrel.SelectBuilder) squirrel.SelectBuilder {
+	for _, field := range p.fields {
+		if field.desc {
+			query = query.OrderBy(field.column + " DESC")
+		} else {
+			query = query.OrderBy(field.column)
+		}
+	}
+	return query
+}
+
+func (p *pager[T]) page(items []T) Page[T] {
+	if len(items) <= p.limit {
+		return Page[T]{Items: items}
+	}
+
+	items = items[:p.limit]
+	if p.byOffset {
+		return Page[T]{Items: items, NextCursor: encodeCursor(cursor{Offset: p.offset + p.limit})}
+	}
+
+	last := items[len(items)-1]
+	c := cursor{Sort: p.sort}
+	for _, field := range p.fields {
+		raw, _ := json.Marshal(field.value(last))
+		c.Values = append(c.Values, raw)
+	}
+	return Page[T]{Items: items, NextCursor: encodeCursor(c)}
+}
@@ -0,0 +1,242 @@
+package pgdb
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"time-tracker/internal/repository/repoerr"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type testRow struct {
+	ID        int
+	Surname   string
+	CreatedAt time.Time
+}
+
+var testColumns = map[string]sortColumn[testRow]{
+	"id":         {"id", func(r testRow) any { return r.ID }},
+	"surname":    {"surname", func(r testRow) any { return r.Surname }},
+	"created_at": {"created_at", func(r testRow) any { return r.CreatedAt }},
+}
+
+func testRows(n int) []testRow {
+	rows := make([]testRow, n)
+	for i := range rows {
+		rows[i] = testRow{
+			ID:        i + 1,
+			Surname:   "Ivanov",
+			CreatedAt: time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+		}
+	}
+	return rows
+}
+
+func testQuery() squirrel.SelectBuilder {
+	return squirrel.Select("*").From("users").PlaceholderFormat(squirrel.Dollar)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		c    cursor
+	}{
+		{"keyset", cursor{Sort: "surname,-created_at", Values: []json.RawMessage{json.RawMessage(`"Ivanov"`), json.RawMessage(`7`)}}},
+		{"offset", cursor{Offset: 20}},
+		{"empty", cursor{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(encodeCursor(tt.c))
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.c) {
+				t.Errorf("decodeCursor = %+v, want %+v", got, tt.c)
+			}
+		})
+	}
+}
+
+func TestKeysetPager(t *testing.T) {
+	tests := []struct {
+		name     string
+		sort     string
+		wantSQL  string
+		wantArgs int
+	}{
+		{
+			name:     "default sort",
+			sort:     "",
+			wantSQL:  "SELECT * FROM users WHERE ((id > $1)) ORDER BY id LIMIT 3",
+			wantArgs: 1,
+		},
+		{
+			name:     "descending",
+			sort:     "-created_at",
+			wantSQL:  "SELECT * FROM users WHERE ((created_at < $1) OR (created_at = $2 AND id > $3)) ORDER BY created_at DESC, id LIMIT 3",
+			wantArgs: 3,
+		},
+		{
+			name: "mixed directions",
+			sort: "surname,-created_at",
+			wantSQL: "SELECT * FROM users WHERE ((surname > $1) OR (surname = $2 AND created_at < $3) OR " +
+				"(surname = $4 AND created_at = $5 AND id > $6)) ORDER BY surname, created_at DESC, id LIMIT 3",
+			wantArgs: 6,
+		},
+		{
+			name:     "descending id",
+			sort:     "surname,-id",
+			wantSQL:  "SELECT * FROM users WHERE ((surname > $1) OR (surname = $2 AND id < $3)) ORDER BY surname, id DESC LIMIT 3",
+			wantArgs: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := newKeysetPager(PageRequest{Limit: 2, Sort: tt.sort}, 100, testColumns, "id")
+			if err != nil {
+				t.Fatalf("newKeysetPager: %v", err)
+			}
+			page := first.page(testRows(3))
+			if len(page.Items) != 2 || page.NextCursor == "" {
+				t.Fatalf("page = %d items, cursor %q, want 2 items and a cursor", len(page.Items), page.NextCursor)
+			}
+
+			next, err := newKeysetPager(PageRequest{Limit: 2, Sort: tt.sort, Cursor: page.NextCursor}, 100, testColumns, "id")
+			if err != nil {
+				t.Fatalf("newKeysetPager with the cursor: %v", err)
+			}
+			sql, args, err := next.apply(testQuery()).ToSql()
+			if err != nil {
+				t.Fatalf("ToSql: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("sql = %q\nwant %q", sql, tt.wantSQL)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("args = %v, want %d", args, tt.wantArgs)
+			}
+			// the values come from the last item of the page and keep the column types
+			for _, arg := range args {
+				switch arg.(type) {
+				case int:
+					if arg != 2 {
+						t.Errorf("id arg = %v, want 2", arg)
+					}
+				case string:
+					if arg != "Ivanov" {
+						t.Errorf("surname arg = %v, want Ivanov", arg)
+					}
+				case time.Time:
+					if !arg.(time.Time).Equal(testRows(2)[1].CreatedAt) {
+						t.Errorf("created_at arg = %v, want %v", arg, testRows(2)[1].CreatedAt)
+					}
+				default:
+					t.Errorf("arg %v has type %T", arg, arg)
+				}
+			}
+		})
+	}
+}
+
+func TestKeysetPagerFirstAndLastPage(t *testing.T) {
+	p, err := newKeysetPager(PageRequest{Limit: 2, Sort: "-created_at"}, 100, testColumns, "id")
+	if err != nil {
+		t.Fatalf("newKeysetPager: %v", err)
+	}
+	sql, _, err := p.apply(testQuery()).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql: %v", err)
+	}
+	if want := "SELECT * FROM users ORDER BY created_at DESC, id LIMIT 3"; sql != want {
+		t.Errorf("sql = %q, want %q", sql, want)
+	}
+	if page := p.page(testRows(2)); page.NextCursor != "" {
+		t.Errorf("NextCursor = %q on the last page", page.NextCursor)
+	}
+}
+
+func TestKeysetPagerErrors(t *testing.T) {
+	p, err := newKeysetPager(PageRequest{Limit: 1, Sort: "surname"}, 100, testColumns, "id")
+	if err != nil {
+		t.Fatalf("newKeysetPager: %v", err)
+	}
+	surnameCursor := p.page(testRows(2)).NextCursor
+
+	tests := []struct {
+		name    string
+		page    PageRequest
+		wantErr error
+	}{
+		{"unknown field", PageRequest{Sort: "passport"}, repoerr.ErrInvalidSort},
+		{"repeated field", PageRequest{Sort: "surname,-surname"}, repoerr.ErrInvalidSort},
+		{"not base64", PageRequest{Cursor: "!!!"}, repoerr.ErrInvalidCursor},
+		{"not json", PageRequest{Cursor: "bm90IGpzb24"}, repoerr.ErrInvalidCursor},
+		{"other sort", PageRequest{Sort: "-surname", Cursor: surnameCursor}, repoerr.ErrInvalidCursor},
+		{"value count", PageRequest{Sort: "surname", Cursor: encodeCursor(cursor{Sort: "surname", Values: []json.RawMessage{json.RawMessage(`"Ivanov"`)}})}, repoerr.ErrInvalidCursor},
+		{"value type", PageRequest{Sort: "surname", Cursor: encodeCursor(cursor{Sort: "surname", Values: []json.RawMessage{json.RawMessage(`"Ivanov"`), json.RawMessage(`"1"`)}})}, repoerr.ErrInvalidCursor},
+		{"offset cursor", PageRequest{Cursor: encodeCursor(cursor{Offset: 10})}, repoerr.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newKeysetPager(tt.page, 100, testColumns, "id")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("newKeysetPager error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOffsetPager(t *testing.T) {
+	p, err := newOffsetPager[testRow](PageRequest{Limit: 2}, 100)
+	if err != nil {
+		t.Fatalf("newOffsetPager: %v", err)
+	}
+	page := p.page(testRows(3))
+	if len(page.Items) != 2 {
+		t.Fatalf("page = %d items, want 2", len(page.Items))
+	}
+
+	next, err := newOffsetPager[testRow](PageRequest{Limit: 2, Cursor: page.NextCursor}, 100)
+	if err != nil {
+		t.Fatalf("newOffsetPager with the cursor: %v", err)
+	}
+	sql, _, err := next.apply(testQuery()).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql: %v", err)
+	}
+	if want := "SELECT * FROM users LIMIT 3 OFFSET 2"; sql != want {
+		t.Errorf("sql = %q, want %q", sql, want)
+	}
+
+	for name, value := range map[string]string{
+		"negative offset": encodeCursor(cursor{Offset: -1}),
+		"keyset cursor":   encodeCursor(cursor{Sort: "id", Values: []json.RawMessage{json.RawMessage(`1`)}}),
+		"not base64":      "!!!",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newOffsetPager[testRow](PageRequest{Cursor: value}, 100); !errors.Is(err, repoerr.ErrInvalidCursor) {
+				t.Errorf("newOffsetPager error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	tests := []struct {
+		limit, maxLimit, want int
+	}{
+		{0, 100, defaultPaginationLimit},
+		{-5, 100, defaultPaginationLimit},
+		{20, 100, 20},
+		{500, 100, 100},
+	}
+	for _, tt := range tests {
+		if got := pageLimit(tt.limit, tt.maxLimit); got != tt.want {
+			t.Errorf("pageLimit(%d, %d) = %d, want %d", tt.limit, tt.maxLimit, got, tt.want)
+		}
+	}
+}
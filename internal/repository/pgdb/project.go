@@ -0,0 +1,113 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolation = "23505"
+
+var projectColumns = []string{"id", "name", "client_id", "created_at", "updated_at"}
+
+type ProjectRepo struct {
+	*postgres.Postgres
+}
+
+func NewProjectRepo(db *postgres.Postgres) *ProjectRepo {
+	return &ProjectRepo{db}
+}
+
+type CreateProjectInput struct {
+	Name     string
+	ClientID *int
+}
+
+// CreateProject returns ErrAlreadyExists when a project with the same name in any case exists.
+func (r *ProjectRepo) CreateProject(ctx context.Context, data CreateProjectInput) (int, error) {
+	var ID int
+	sql, args, _ := r.Builder.Insert("md.projects").
+		Columns("name", "client_id", "created_at").
+		Values(data.Name, data.ClientID, time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, repoerr.ErrAlreadyExists
+		}
+		return 0, fmt.Errorf("ProjectRepo.CreateProject - r.Conn.QueryRow: %v", err)
+	}
+	return ID, nil
+}
+
+func (r *ProjectRepo) GetProject(ctx context.Context, ID int) (model.Project, error) {
+	sql, args, _ := r.Builder.Select(projectColumns...).From("md.projects").Where("id = ?", ID).ToSql()
+	return r.getProject(ctx, "ProjectRepo.GetProject", sql, args)
+}
+
+// GetProjectByName matches the name case insensitively.
+func (r *ProjectRepo) GetProjectByName(ctx context.Context, name string) (model.Project, error) {
+	sql, args, _ := r.Builder.Select(projectColumns...).From("md.projects").Where("lower(name) = lower(?)", name).ToSql()
+	return r.getProject(ctx, "ProjectRepo.GetProjectByName", sql, args)
+}
+
+func (r *ProjectRepo) getProject(ctx context.Context, method, sql string, args []any) (model.Project, error) {
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return model.Project{}, fmt.Errorf("%s - r.Conn.Query: %v", method, err)
+	}
+	project, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[model.Project])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return project, repoerr.ErrNotFound
+		}
+		return project, fmt.Errorf("%s - pgx.CollectOneRow: %v", method, err)
+	}
+	return project, nil
+}
+
+func (r *ProjectRepo) ListProjects(ctx context.Context) ([]model.Project, error) {
+	sql, args, _ := r.Builder.Select(projectColumns...).From("md.projects").OrderBy("name", "id").ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProjectRepo.ListProjects - r.Conn.Query: %v", err)
+	}
+	projects, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.Project])
+	if err != nil {
+		return nil, fmt.Errorf("ProjectRepo.ListProjects - pgx.CollectRows: %v", err)
+	}
+	return projects, nil
+}
+
+// SetProjectClient moves the project to the client, nil clientID leaves it without one.
+func (r *ProjectRepo) SetProjectClient(ctx context.Context, ID int, clientID *int) error {
+	sql, args, _ := r.Builder.Update("md.projects").
+		Set("client_id", clientID).
+		Set("updated_at", time.Now()).
+		Where("id = ?", ID).
+		ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ProjectRepo.SetProjectClient - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
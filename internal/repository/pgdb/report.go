@@ -0,0 +1,120 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/pkg/postgres"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type ReportRepo struct {
+	*postgres.Postgres
+}
+
+func NewReportRepo(db *postgres.Postgres) *ReportRepo {
+	return &ReportRepo{db}
+}
+
+// WorklogFilter leaves the period open on the side whose date is zero, UserID 0 reports every user.
+type WorklogFilter struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	UserID   int
+}
+
+// ForEachWorklogEntry streams the time tracked per user ordered by surname and name, users of any
+// lifecycle status are reported.
+func (r *ReportRepo) ForEachWorklogEntry(ctx context.Context, filter WorklogFilter, fn func(model.WorklogEntry) error) error {
+	where := squirrel.And{squirrel.Eq{"t.deleted_at": nil, "u.deleted_at": nil}}
+	if !filter.DateFrom.IsZero() {
+		where = append(where, squirrel.GtOrEq{"t.created_at": filter.DateFrom})
+	}
+	if !filter.DateTo.IsZero() {
+		where = append(where, squirrel.LtOrEq{"t.created_at": filter.DateTo})
+	}
+	if filter.UserID != 0 {
+		where = append(where, squirrel.Eq{"u.id": filter.UserID})
+	}
+	sql, args, _ := r.Builder.
+		Select("u.id", "u.username", "u.surname", "u.patronymic", "md.user_status(u.id)",
+			"count(t.id)", "COALESCE(sum(t.duration), 0)",
+		).
+		From("md.tasks t").
+		Join("md.users u ON u.id = t.user_id").
+		Where(where).
+		GroupBy("u.id").
+		OrderBy("u.surname", "u.username", "u.id").
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ReportRepo.ForEachWorklogEntry - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.WorklogEntry
+		err := rows.Scan(&e.UserID, &e.Name, &e.Surname, &e.Patronymic, &e.Status, &e.Tasks, &e.Duration)
+		if err != nil {
+			return fmt.Errorf("ReportRepo.ForEachWorklogEntry - rows.Scan: %v", err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ReportRepo.ForEachWorklogEntry - rows.Err: %v", err)
+	}
+	return nil
+}
+
+// ForEachClientWorklogEntry streams the time tracked per client ordered by name, the time without a client
+// comes last.
+func (r *ReportRepo) ForEachClientWorklogEntry(ctx context.Context, filter WorklogFilter, fn func(model.ClientWorklogEntry) error) error {
+	where := squirrel.And{squirrel.Eq{"t.deleted_at": nil, "u.deleted_at": nil}}
+	if !filter.DateFrom.IsZero() {
+		where = append(where, squirrel.GtOrEq{"t.created_at": filter.DateFrom})
+	}
+	if !filter.DateTo.IsZero() {
+		where = append(where, squirrel.LtOrEq{"t.created_at": filter.DateTo})
+	}
+	if filter.UserID != 0 {
+		where = append(where, squirrel.Eq{"u.id": filter.UserID})
+	}
+	sql, args, _ := r.Builder.
+		Select("c.id", "COALESCE(c.name, '')", "count(DISTINCT t.project_id)", "count(t.id)",
+			"COALESCE(sum(t.duration), 0)", "COALESCE(sum(t.duration) FILTER (WHERE t.billable), 0)",
+		).
+		From("md.tasks t").
+		Join("md.users u ON u.id = t.user_id").
+		LeftJoin("md.projects p ON p.id = t.project_id").
+		LeftJoin("md.clients c ON c.id = p.client_id").
+		Where(where).
+		GroupBy("c.id").
+		OrderBy("c.name NULLS LAST", "c.id").
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ReportRepo.ForEachClientWorklogEntry - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.ClientWorklogEntry
+		err := rows.Scan(&e.ClientID, &e.Name, &e.Projects, &e.Tasks, &e.Duration, &e.BillableDuration)
+		if err != nil {
+			return fmt.Errorf("ReportRepo.ForEachClientWorklogEntry - rows.Scan: %v", err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ReportRepo.ForEachClientWorklogEntry - rows.Err: %v", err)
+	}
+	return nil
+}
@@ -9,85 +9,266 @@ import (
 	"time-tracker/internal/repository/repoerr"
 	"time-tracker/pkg/postgres"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
 )
 
+var taskColumns = []string{
+	"id", "user_id", "project_id", "description", "duration", "completed", "billable", "invoice_id",
+	"created_at", "updated_at", "deleted_at", "external_id",
+}
+
+// taskSortColumns are the fields ListTasks sorts by, named after the model.Task JSON fields.
+var taskSortColumns = map[string]sortColumn[model.Task]{
+	"id":          {"id", func(t model.Task) any { return t.ID }},
+	"description": {"description", func(t model.Task) any { return t.Description }},
+	"duration":    {"duration", func(t model.Task) any { return t.Duration }},
+	"created_at":  {"created_at", func(t model.Task) any { return t.CreatedAt }},
+}
+
 type TaskRepo struct {
 	*postgres.Postgres
+	maxPageLimit int
 }
 
-func NewTaskRepo(db *postgres.Postgres) *TaskRepo {
-	return &TaskRepo{db}
+func NewTaskRepo(db *postgres.Postgres, maxPageLimit int) *TaskRepo {
+	return &TaskRepo{db, maxPageLimit}
+}
+
+func scanTask(row pgx.Row, task *model.Task) error {
+	return row.Scan(
+		&task.ID, &task.UserID, &task.ProjectID, &task.Description, &task.Duration, &task.Completed,
+		&task.Billable, &task.InvoiceID, &task.CreatedAt, &task.UpdatedAt, &task.DeletedAt, &task.ExternalID,
+	)
 }
 
 type CreateTaskInput struct {
 	UserID      int
+	ProjectID   *int
 	Description string
+	Billable    bool
 }
 
+// CreateTask creates the task along with its model.EventTaskCreated outbox event.
 func (r *TaskRepo) CreateTask(ctx context.Context, data CreateTaskInput) (int, error) {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("TaskRepo.CreateTask - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	var ID int
 	sql, args, _ := r.Builder.Insert("md.tasks").
-		Columns("user_id", "description", "completed", "duration", "created_at").
-		Values(data.UserID, data.Description, false, 0, time.Now()).
+		Columns("user_id", "project_id", "description", "billable", "completed", "duration", "created_at").
+		Values(data.UserID, data.ProjectID, data.Description, data.Billable, false, 0, time.Now()).
 		Suffix("RETURNING id").
 		ToSql()
 
-	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&ID)
+	err = tx.QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		return 0, fmt.Errorf("TaskRepo.CreateTask - tx.QueryRow: %v", err)
+	}
+	err = r.addTaskEvent(ctx, tx, ID, model.EventTaskCreated)
 	if err != nil {
-		return 0, fmt.Errorf("TaskRepo.CreateTask - r.Pool.QueryRow: %v", err)
+		return 0, fmt.Errorf("TaskRepo.CreateTask - r.addTaskEvent: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("TaskRepo.CreateTask - tx.Commit: %v", err)
 	}
 	return ID, nil
 }
 
-func (r *TaskRepo) GetTask(ctx context.Context, ID int) (model.Task, error) {
+// addTaskEvent writes the event with the task as it is within tx to the outbox.
+func (r *TaskRepo) addTaskEvent(ctx context.Context, tx pgx.Tx, ID int, event string) error {
 	var task model.Task
+	sql, args, _ := r.Builder.Select(taskColumns...).From("md.tasks").Where("id = ?", ID).ToSql()
+	err := scanTask(tx.QueryRow(ctx, sql, args...), &task)
+	if err != nil {
+		return fmt.Errorf("scanTask: %v", err)
+	}
+	return addOutboxEvent(ctx, tx, r.Builder, model.AggregateTask, ID, event, task)
+}
 
-	sql, args, _ := r.Builder.Select("*").From("md.tasks").Where("id = ?", ID).ToSql()
+// ImportTaskInput is a completed time entry from another tracker, Start and End bound the tracked interval.
+type ImportTaskInput struct {
+	UserID      int
+	ProjectID   *int
+	Description string
+	Billable    bool
+	Start       time.Time
+	End         time.Time
+	ExternalID  string
+}
 
-	err := r.Pool.QueryRow(ctx, sql, args...).Scan(
-		&task.ID, &task.UserID, &task.Description, &task.Duration, &task.Completed,
-		&task.CreatedAt, &task.UpdatedAt,
-	)
+// ImportTask creates a completed task once per ExternalID along with its model.EventTaskCreated outbox event,
+// ErrAlreadyExists is returned for a repeated one.
+func (r *TaskRepo) ImportTask(ctx context.Context, data ImportTaskInput) (int, error) {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("TaskRepo.ImportTask - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var ID int
+	sql, args, _ := r.Builder.Insert("md.tasks").
+		Columns("user_id", "project_id", "description", "billable", "completed", "duration", "created_at", "updated_at", "external_id").
+		Values(data.UserID, data.ProjectID, data.Description, data.Billable, true, int(data.End.Sub(data.Start).Minutes()),
+			data.Start, data.End, data.ExternalID).
+		Suffix("ON CONFLICT (external_id) DO NOTHING RETURNING id").
+		ToSql()
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repoerr.ErrAlreadyExists
+		}
+		return 0, fmt.Errorf("TaskRepo.ImportTask - tx.QueryRow: %v", err)
+	}
+	err = r.addTaskEvent(ctx, tx, ID, model.EventTaskCreated)
+	if err != nil {
+		return 0, fmt.Errorf("TaskRepo.ImportTask - r.addTaskEvent: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("TaskRepo.ImportTask - tx.Commit: %v", err)
+	}
+	return ID, nil
+}
+
+// GetTaskIDsByExternalID maps the external IDs already imported to their tasks, deleted tasks included.
+func (r *TaskRepo) GetTaskIDsByExternalID(ctx context.Context, externalIDs []string) (map[string]int, error) {
+	sql, args, _ := r.Builder.Select("external_id", "id").
+		From("md.tasks").
+		Where("external_id = ANY(?)", externalIDs).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("TaskRepo.GetTaskIDsByExternalID - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	IDs := make(map[string]int)
+	for rows.Next() {
+		var externalID string
+		var ID int
+		if err := rows.Scan(&externalID, &ID); err != nil {
+			return nil, fmt.Errorf("TaskRepo.GetTaskIDsByExternalID - rows.Scan: %v", err)
+		}
+		IDs[externalID] = ID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TaskRepo.GetTaskIDsByExternalID - rows.Err: %v", err)
+	}
+	return IDs, nil
+}
+
+func (r *TaskRepo) GetTask(ctx context.Context, ID int, includeDeleted bool) (model.Task, error) {
+	var task model.Task
+
+	query := r.Builder.Select(taskColumns...).From("md.tasks").Where("id = ?", ID)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	sql, args, _ := query.ToSql()
+
+	err := scanTask(r.Conn(ctx).QueryRow(ctx, sql, args...), &task)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return task, repoerr.ErrNotFound
 		}
-		return task, fmt.Errorf("TaskRepo.GetTask - r.Pool.QueryRow: %v", err)
+		return task, fmt.Errorf("TaskRepo.GetTask - r.Conn.QueryRow: %v", err)
 	}
 	return task, nil
 }
 
+// ListTasksFilter leaves the period open on the side whose date is zero.
 type ListTasksFilter struct {
-	DateTo   time.Time
-	DateFrom time.Time
+	DateTo         time.Time
+	DateFrom       time.Time
+	IncludeDeleted bool
+	PageRequest
 }
 
-func (r *TaskRepo) ListTasks(ctx context.Context, userID int, filter ListTasksFilter) ([]model.Task, error) {
-	sql, args, _ := r.Builder.Select("*").From("md.tasks").
-		Where("user_id = ? AND created_at BETWEEN ? AND ?", userID, filter.DateFrom, filter.DateTo).
-		OrderBy("duration DESC").
-		ToSql()
+// ListTasks sorts by filter.Sort, the longest tasks come first by default.
+func (r *TaskRepo) ListTasks(ctx context.Context, userID int, filter ListTasksFilter) (Page[model.Task], error) {
+	var page Page[model.Task]
+	pager, err := newKeysetPager(filter.PageRequest, r.maxPageLimit, taskSortColumns, "-duration")
+	if err != nil {
+		return page, err
+	}
 
-	rows, err := r.Pool.Query(ctx, sql, args...)
+	where := tasksWhere(userID, filter)
+	sql, args, _ := pager.apply(r.Builder.Select(taskColumns...).From("md.tasks").Where(where)).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("TaskRepo.ListTasks - r.Pool.Query: %v", err)
+		return page, fmt.Errorf("TaskRepo.ListTasks - r.Conn.Query: %v", err)
 	}
 	defer rows.Close()
 
 	var tasks []model.Task
 	for rows.Next() {
 		var task model.Task
-		if err := rows.Scan(
-			&task.ID, &task.UserID, &task.Description, &task.Duration, &task.Completed,
-			&task.CreatedAt, &task.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("TaskRepo.ListTasks - rows.Scan: %v", err)
+		if err := scanTask(rows, &task); err != nil {
+			return page, fmt.Errorf("TaskRepo.ListTasks - rows.Scan: %v", err)
 		}
 		tasks = append(tasks, task)
 	}
+	page = pager.page(tasks)
 
-	return tasks, nil
+	if filter.WithTotal {
+		sql, args, _ = r.Builder.Select("count(*)").From("md.tasks").Where(where).ToSql()
+		err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&page.Total)
+		if err != nil {
+			return page, fmt.Errorf("TaskRepo.ListTasks - count - r.Conn.QueryRow: %v", err)
+		}
+	}
+	return page, nil
+}
+
+// ForEachTask streams the tasks matching the filter in filter.Sort order to fn, paging is ignored.
+func (r *TaskRepo) ForEachTask(ctx context.Context, userID int, filter ListTasksFilter, fn func(model.Task) error) error {
+	pager, err := newKeysetPager(PageRequest{Sort: filter.Sort}, r.maxPageLimit, taskSortColumns, "-duration")
+	if err != nil {
+		return err
+	}
+	sql, args, _ := pager.order(r.Builder.Select(taskColumns...).From("md.tasks").Where(tasksWhere(userID, filter))).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("TaskRepo.ForEachTask - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task model.Task
+		if err := scanTask(rows, &task); err != nil {
+			return fmt.Errorf("TaskRepo.ForEachTask - rows.Scan: %v", err)
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("TaskRepo.ForEachTask - rows.Err: %v", err)
+	}
+	return nil
+}
+
+func tasksWhere(userID int, filter ListTasksFilter) squirrel.And {
+	where := squirrel.And{squirrel.Eq{"user_id": userID}}
+	if !filter.DateFrom.IsZero() {
+		where = append(where, squirrel.GtOrEq{"created_at": filter.DateFrom})
+	}
+	if !filter.DateTo.IsZero() {
+		where = append(where, squirrel.LtOrEq{"created_at": filter.DateTo})
+	}
+	if !filter.IncludeDeleted {
+		where = append(where, squirrel.Eq{"deleted_at": nil})
+	}
+	return where
 }
 
 type UpdateTaskInput struct {
@@ -96,7 +277,14 @@ type UpdateTaskInput struct {
 	UpdatedAt time.Time
 }
 
+// UpdateTask writes the model.EventTaskCompleted outbox event along with an update completing the task.
 func (r *TaskRepo) UpdateTask(ctx context.Context, ID int, data UpdateTaskInput) error {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("TaskRepo.UpdateTask - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	sql, args, _ := r.Builder.Update("md.tasks").
 		SetMap(map[string]interface{}{
 			"completed":  data.Completed,
@@ -106,9 +294,71 @@ func (r *TaskRepo) UpdateTask(ctx context.Context, ID int, data UpdateTaskInput)
 		Where("id = ?", ID).
 		ToSql()
 
-	_, err := r.Pool.Exec(ctx, sql, args...)
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("TaskRepo.UpdateTask - tx.Exec: %v", err)
+	}
+	if data.Completed {
+		err = r.addTaskEvent(ctx, tx, ID, model.EventTaskCompleted)
+		if err != nil {
+			return fmt.Errorf("TaskRepo.UpdateTask - r.addTaskEvent: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("TaskRepo.UpdateTask - tx.Commit: %v", err)
+	}
+	return nil
+}
+
+// DeleteTask soft deletes the task, invoiced tasks are kept as billed and are not found.
+func (r *TaskRepo) DeleteTask(ctx context.Context, ID int) error {
+	sql, args, _ := r.Builder.Update("md.tasks").
+		Set("deleted_at", time.Now()).
+		Where("id = ? AND deleted_at IS NULL AND invoice_id IS NULL", ID).
+		ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("TaskRepo.UpdateTask - r.Pool.Exec: %v", err)
+		return fmt.Errorf("TaskRepo.DeleteTask - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
 	}
 	return nil
 }
+
+func (r *TaskRepo) RestoreTask(ctx context.Context, ID int) error {
+	sql, args, _ := r.Builder.Update("md.tasks").
+		Set("deleted_at", nil).
+		Where("id = ? AND deleted_at IS NOT NULL", ID).
+		ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("TaskRepo.RestoreTask - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeTasks permanently removes tasks soft deleted before the given time.
+func (r *TaskRepo) PurgeTasks(ctx context.Context, deletedBefore time.Time) ([]int, error) {
+	// tasks deleted before invoices were introduced may be invoiced, the invoice keeps them
+	sql, args, _ := r.Builder.Delete("md.tasks").
+		Where("deleted_at < ? AND invoice_id IS NULL", deletedBefore).
+		Suffix("RETURNING id").
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("TaskRepo.PurgeTasks - r.Conn.Query: %v", err)
+	}
+	IDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("TaskRepo.PurgeTasks - pgx.CollectRows: %v", err)
+	}
+	return IDs, nil
+}
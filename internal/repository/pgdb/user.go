@@ -4,154 +4,458 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 	"time-tracker/internal/model"
 	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/document"
+	"time-tracker/pkg/envelope"
 	"time-tracker/pkg/postgres"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
 )
 
-const (
-	maxPaginationLimit     = 10
-	defaultPaginationLimit = 10
-)
+const reencryptBatchSize = 100
 
+var userColumns = []string{
+	"id", "username", "surname", "patronymic", "passport_number", "document_type", "document_country", "address", "email",
+	"md.user_status(id) AS status", "enrichment_status", "profile_pinned", "synced_at",
+	"created_at", "updated_at", "deleted_at", "anonymized_at", "merged_into_id",
+}
+
+// userSortColumns are the fields ListUsersPagination sorts by, named after the model.User JSON fields.
+var userSortColumns = map[string]sortColumn[model.User]{
+	"id":         {"id", func(u model.User) any { return u.ID }},
+	"name":       {"username", func(u model.User) any { return u.Name }},
+	"surname":    {"surname", func(u model.User) any { return u.Surname }},
+	"patronymic": {"patronymic", func(u model.User) any { return u.Patronymic }},
+	"created_at": {"created_at", func(u model.User) any { return u.CreatedAt }},
+}
+
+// UserRepo stores passport numbers encrypted, lookups by passport number go through the passport_hash blind index.
 type UserRepo struct {
 	*postgres.Postgres
+	envelope     *envelope.Envelope
+	maxPageLimit int
+}
+
+func NewUserRepo(db *postgres.Postgres, envelope *envelope.Envelope, maxPageLimit int) *UserRepo {
+	return &UserRepo{db, envelope, maxPageLimit}
 }
 
-func NewUserRepo(db *postgres.Postgres) *UserRepo {
-	return &UserRepo{db}
+// scanUser reads userColumns followed by the extra destinations.
+func (r *UserRepo) scanUser(row pgx.Row, user *model.User, extra ...any) error {
+	dest := []any{
+		&user.ID, &user.Name, &user.Surname, &user.Patronymic, &user.PassportNumber, &user.DocumentType, &user.DocumentCountry, &user.Address, &user.Email,
+		&user.Status, &user.EnrichmentStatus, &user.ProfilePinned, &user.SyncedAt,
+		&user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.AnonymizedAt, &user.MergedIntoID,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return err
+	}
+
+	passportNumber, err := r.envelope.Decrypt(user.PassportNumber)
+	if err != nil {
+		// rows written before encryption was introduced stay readable until BackfillPassportHashes encrypts them
+		if errors.Is(err, envelope.ErrNotEncrypted) {
+			return nil
+		}
+		return fmt.Errorf("r.envelope.Decrypt: %v", err)
+	}
+	user.PassportNumber = passportNumber
+	return nil
+}
+
+// documentHash is the blind index of a document, russian passports keep the plain number HMAC they had
+// before other document types were introduced.
+func (r *UserRepo) documentHash(doc document.Document) string {
+	if doc.Type == "" || doc.Type == document.TypeRUPassport {
+		return r.envelope.BlindIndex(doc.Number)
+	}
+	return r.envelope.BlindIndex(doc.Type + ":" + doc.Country + ":" + doc.Number)
 }
 
 type CreateUserInput struct {
-	Name           string `json:"name"`
-	Surname        string `json:"surname"`
-	Patronymic     string `json:"patronymic"`
-	PassportNumber string `json:"passport_number"`
-	Address        string `json:"address"`
+	Name            string `json:"name"`
+	Surname         string `json:"surname"`
+	Patronymic      string `json:"patronymic"`
+	PassportNumber  string `json:"passport_number"`
+	DocumentType    string `json:"document_type"`
+	DocumentCountry string `json:"document_country"`
+	Address         string `json:"address"`
+	Email           string `json:"email"`
+
+	EnrichmentStatus string `json:"enrichment_status"`
 }
 
+func (data CreateUserInput) document() document.Document {
+	return document.Document{Type: data.DocumentType, Country: data.DocumentCountry, Number: data.PassportNumber}
+}
+
+// CreateUser creates the user along with its model.EventUserCreated outbox event.
 func (r *UserRepo) CreateUser(ctx context.Context, data CreateUserInput) (int, error) {
 	var ID int
+	passportNumber, err := r.envelope.Encrypt(data.PassportNumber)
+	if err != nil {
+		return 0, fmt.Errorf("UserRepo.CreateUser - r.envelope.Encrypt: %v", err)
+	}
+
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("UserRepo.CreateUser - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	sql, args, _ := r.Builder.Insert("md.users").
-		Columns("username", "surname", "patronymic", "passport_number", "address", "created_at").
-		Values(data.Name, data.Surname, data.Patronymic, data.PassportNumber, data.Address, time.Now()).
+		Columns("username", "surname", "patronymic", "passport_number", "passport_hash", "document_type", "document_country", "address", "email", "enrichment_status", "created_at").
+		Values(data.Name, data.Surname, data.Patronymic, passportNumber, r.documentHash(data.document()), data.DocumentType, data.DocumentCountry, data.Address, data.Email, data.EnrichmentStatus, time.Now()).
 		Suffix("RETURNING id").
 		ToSql()
 
-	err := r.Pool.QueryRow(ctx, sql, args...).Scan(&ID)
+	err = tx.QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		return 0, fmt.Errorf("UserRepo.CreateUser - tx.QueryRow: %v", err)
+	}
+	err = r.addUserEvent(ctx, tx, ID, model.EventUserCreated)
 	if err != nil {
-		return 0, fmt.Errorf("UserRepo.CreateUser - r.Pool.QueryRow: %v", err)
+		return 0, fmt.Errorf("UserRepo.CreateUser - r.addUserEvent: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("UserRepo.CreateUser - tx.Commit: %v", err)
 	}
 	return ID, nil
 }
 
-func (r *UserRepo) GetUser(ctx context.Context, ID int) (model.User, error) {
+// addUserEvent writes the event with the user as it is within tx to the outbox. Published events and webhook
+// deliveries outlive an anonymization, so personal data is left out of it, consumers fetch the user by id.
+func (r *UserRepo) addUserEvent(ctx context.Context, tx pgx.Tx, ID int, event string) error {
 	var user model.User
+	sql, args, _ := r.Builder.Select(userColumns...).From("md.users").Where("id = ?", ID).ToSql()
+	err := r.scanUser(tx.QueryRow(ctx, sql, args...), &user)
+	if err != nil {
+		return fmt.Errorf("r.scanUser: %v", err)
+	}
+	return addOutboxEvent(ctx, tx, r.Builder, model.AggregateUser, ID, event, map[string]any{
+		"id":         user.ID,
+		"status":     user.Status,
+		"created_at": user.CreatedAt,
+		"deleted_at": user.DeletedAt,
+	})
+}
 
-	sql, args, err := r.Builder.Select("*").From("md.users").Where("id = ?", ID).ToSql()
+func (r *UserRepo) GetUser(ctx context.Context, ID int, includeDeleted bool) (model.User, error) {
+	var user model.User
+
+	query := r.Builder.Select(userColumns...).From("md.users").Where("id = ?", ID)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return user, fmt.Errorf("UserRepo.GetUser - r.Builder.ToSql: %v", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(
-		&user.ID, &user.Name, &user.Surname, &user.Patronymic, &user.PassportNumber, &user.Address, &user.CreatedAt, &user.UpdatedAt,
-	)
+	err = r.scanUser(r.Conn(ctx).QueryRow(ctx, sql, args...), &user)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return user, repoerr.ErrNotFound
 		}
-		return user, fmt.Errorf("SourceRepo.GetRouteBySourceID - r.Pool.QueryRow: %v", err)
+		return user, fmt.Errorf("UserRepo.GetUser - r.Conn.QueryRow: %v", err)
 	}
 
 	return user, nil
 }
 
-func (r *UserRepo) GeUsertByPassportNumber(ctx context.Context, passportNumber string) (model.User, error) {
+// GeUsertByPassportNumber finds the user by an identity document, soft deleted users too,
+// the document stays reserved until purge.
+func (r *UserRepo) GeUsertByPassportNumber(ctx context.Context, doc document.Document) (model.User, error) {
 	var user model.User
 
-	sql, args, err := r.Builder.Select("*").From("md.users").Where("passport_number = ?", passportNumber).ToSql()
+	sql, args, err := r.Builder.Select(userColumns...).From("md.users").Where("passport_hash = ?", r.documentHash(doc)).ToSql()
 	if err != nil {
-		return user, fmt.Errorf("UserRepo.GetUser - r.Builder.ToSql: %v", err)
+		return user, fmt.Errorf("UserRepo.GeUsertByPassportNumber - r.Builder.ToSql: %v", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(
-		&user.ID, &user.Name, &user.Surname, &user.Patronymic, &user.PassportNumber, &user.Address, &user.CreatedAt, &user.UpdatedAt,
-	)
+	err = r.scanUser(r.Conn(ctx).QueryRow(ctx, sql, args...), &user)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return user, repoerr.ErrNotFound
 		}
-		return user, fmt.Errorf("SourceRepo.GetRouteBySourceID - r.Pool.QueryRow: %v", err)
+		return user, fmt.Errorf("UserRepo.GeUsertByPassportNumber - r.Conn.QueryRow: %v", err)
 	}
 
 	return user, nil
 }
 
-func (r *UserRepo) ListUsersPagination(ctx context.Context, name, surname, patronymic, passport_number, address string, limit, offset int) ([]model.User, error) {
-	if limit > maxPaginationLimit {
-		limit = maxPaginationLimit
+type ListUsersFilter struct {
+	Name            string
+	Surname         string
+	Patronymic      string
+	PassportNumber  string // matched exactly with DocumentType and DocumentCountry
+	DocumentType    string
+	DocumentCountry string
+	Address         string
+	Query           string   // fuzzy search, see fuzzySearch
+	Statuses        []string // lifecycle statuses in effect now, any status when empty
+	IncludeDeleted  bool
+	PageRequest
+}
+
+// ListUsersPagination sorts by filter.Sort (id by default), with filter.Query results are ranked by relevance instead.
+func (r *UserRepo) ListUsersPagination(ctx context.Context, filter ListUsersFilter) (Page[model.User], error) {
+	whereClauses := r.usersWhere(filter)
+
+	var page Page[model.User]
+	var pager *pager[model.User]
+	var err error
+	query := r.Builder.Select(userColumns...).From("md.users")
+	if words := strings.Fields(filter.Query); len(words) > 0 {
+		if filter.Sort != "" {
+			return page, fmt.Errorf("%w: q results are ordered by relevance", repoerr.ErrInvalidSort)
+		}
+		match, score := fuzzySearch(words)
+		whereClauses = append(whereClauses, match)
+		query = query.OrderByClause(score).OrderBy("id")
+		pager, err = newOffsetPager[model.User](filter.PageRequest, r.maxPageLimit)
+	} else {
+		pager, err = newKeysetPager(filter.PageRequest, r.maxPageLimit, userSortColumns, "id")
 	}
-	if limit == 0 {
-		limit = defaultPaginationLimit
+	if err != nil {
+		return page, err
 	}
 
-	var whereClauses []squirrel.Sqlizer
-	if name != "" {
-		whereClauses = append(whereClauses, squirrel.ILike{"username": fmt.Sprintf("%%%s%%", name)})
-	}
-	if surname != "" {
-		whereClauses = append(whereClauses, squirrel.ILike{"surname": fmt.Sprint("%%%s%%", surname)})
+	countQuery := r.Builder.Select("count(*)").From("md.users")
+	if len(whereClauses) > 0 {
+		query = query.Where(squirrel.And(whereClauses))
+		countQuery = countQuery.Where(squirrel.And(whereClauses))
 	}
-	if patronymic != "" {
-		whereClauses = append(whereClauses, squirrel.ILike{"patronymic": fmt.Sprint("%%%s%%", patronymic)})
+	sql, args, _ := pager.apply(query).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return page, fmt.Errorf("UserRepo.ListUsersPagination - r.Conn.Query: %v", err)
 	}
-	if passport_number != "" {
-		whereClauses = append(whereClauses, squirrel.ILike{"passport_number": fmt.Sprint("%%%s%%", passport_number)})
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user := model.User{}
+		err = r.scanUser(rows, &user)
+		if err != nil {
+			return page, fmt.Errorf("UserRepo.ListUsersPagination - rows.Scan: %v", err)
+		}
+		users = append(users, user)
 	}
-	if address != "" {
-		whereClauses = append(whereClauses, squirrel.ILike{"address": fmt.Sprint("%%%s%%", address)})
+	page = pager.page(users)
+
+	if filter.WithTotal {
+		sql, args, _ = countQuery.ToSql()
+		err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&page.Total)
+		if err != nil {
+			return page, fmt.Errorf("UserRepo.ListUsersPagination - count - r.Conn.QueryRow: %v", err)
+		}
 	}
+	return page, nil
+}
 
-	query := r.Builder.Select("*").From("md.users").Limit(uint64(limit)).Offset(uint64(offset))
+// ForEachUser streams the users matching the filter to fn in the order ListUsersPagination returns them,
+// paging is ignored.
+func (r *UserRepo) ForEachUser(ctx context.Context, filter ListUsersFilter, fn func(model.User) error) error {
+	whereClauses := r.usersWhere(filter)
+	query := r.Builder.Select(userColumns...).From("md.users")
+	if words := strings.Fields(filter.Query); len(words) > 0 {
+		if filter.Sort != "" {
+			return fmt.Errorf("%w: q results are ordered by relevance", repoerr.ErrInvalidSort)
+		}
+		match, score := fuzzySearch(words)
+		whereClauses = append(whereClauses, match)
+		query = query.OrderByClause(score).OrderBy("id")
+	} else {
+		pager, err := newKeysetPager(PageRequest{Sort: filter.Sort}, r.maxPageLimit, userSortColumns, "id")
+		if err != nil {
+			return err
+		}
+		query = pager.order(query)
+	}
 	if len(whereClauses) > 0 {
 		query = query.Where(squirrel.And(whereClauses))
 	}
 	sql, args, _ := query.ToSql()
 
-	rows, err := r.Pool.Query(ctx, sql, args...)
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("UserRepo.ForEachUser - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := model.User{}
+		if err := r.scanUser(rows, &user); err != nil {
+			return fmt.Errorf("UserRepo.ForEachUser - rows.Scan: %v", err)
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("UserRepo.ForEachUser - rows.Err: %v", err)
+	}
+	return nil
+}
+
+func (r *UserRepo) usersWhere(filter ListUsersFilter) []squirrel.Sqlizer {
+	var whereClauses []squirrel.Sqlizer
+	if filter.Name != "" {
+		whereClauses = append(whereClauses, translitLike("username", filter.Name))
+	}
+	if filter.Surname != "" {
+		whereClauses = append(whereClauses, translitLike("surname", filter.Surname))
+	}
+	if filter.Patronymic != "" {
+		whereClauses = append(whereClauses, translitLike("patronymic", filter.Patronymic))
+	}
+	if filter.PassportNumber != "" {
+		// encrypted values can only be matched exactly
+		whereClauses = append(whereClauses, squirrel.Eq{"passport_hash": r.documentHash(document.Document{
+			Type:    filter.DocumentType,
+			Country: filter.DocumentCountry,
+			Number:  filter.PassportNumber,
+		})})
+	}
+	if filter.DocumentType != "" {
+		whereClauses = append(whereClauses, squirrel.Eq{"document_type": filter.DocumentType})
+	}
+	if filter.DocumentCountry != "" {
+		whereClauses = append(whereClauses, squirrel.Eq{"document_country": filter.DocumentCountry})
+	}
+	if filter.Address != "" {
+		whereClauses = append(whereClauses, squirrel.ILike{"address": fmt.Sprintf("%%%s%%", filter.Address)})
+	}
+	if len(filter.Statuses) > 0 {
+		whereClauses = append(whereClauses, squirrel.Expr("md.user_status(id) = ANY(?)", filter.Statuses))
+	}
+	if !filter.IncludeDeleted {
+		whereClauses = append(whereClauses, squirrel.Eq{"deleted_at": nil})
+	}
+	return whereClauses
+}
+
+// translitLike matches a substring of the column in either cyrillic or latin spelling, see md.search_key.
+func translitLike(column, value string) squirrel.Sqlizer {
+	return squirrel.Expr(fmt.Sprintf("md.search_key(%s) LIKE '%%' || md.search_key(?) || '%%'", column), value)
+}
+
+// fuzzySearch matches users having every word similar (pg_trgm) to one of the name fields or contained in the address
+// with typos, score orders them by the summed best similarity of the words.
+func fuzzySearch(words []string) (match squirrel.Sqlizer, score squirrel.Sqlizer) {
+	const (
+		wordMatch = "(md.search_key(username) % md.search_key(?) OR md.search_key(surname) % md.search_key(?)" +
+			" OR md.search_key(patronymic) % md.search_key(?) OR lower(?) <% lower(address))"
+		wordScore = "GREATEST(similarity(md.search_key(username), md.search_key(?)), similarity(md.search_key(surname), md.search_key(?))," +
+			" similarity(md.search_key(patronymic), md.search_key(?)), word_similarity(lower(?), lower(address)))"
+	)
+
+	matches := make(squirrel.And, 0, len(words))
+	scores := make([]string, 0, len(words))
+	scoreArgs := make([]any, 0, len(words)*4)
+	for _, word := range words {
+		matches = append(matches, squirrel.Expr(wordMatch, word, word, word, word))
+		scores = append(scores, wordScore)
+		scoreArgs = append(scoreArgs, word, word, word, word)
+	}
+	return matches, squirrel.Expr(strings.Join(scores, " + ")+" DESC", scoreArgs...)
+}
+
+// ListUsersByEnrichmentStatus returns the oldest not deleted and not anonymized users in the given enrichment
+// status.
+func (r *UserRepo) ListUsersByEnrichmentStatus(ctx context.Context, status string, limit int) ([]model.User, error) {
+	sql, args, _ := r.Builder.Select(userColumns...).From("md.users").
+		Where("enrichment_status = ? AND deleted_at IS NULL AND anonymized_at IS NULL", status).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("UserRepo.ListUsersPagination - r.Pool.Query: %v", err)
+		return nil, fmt.Errorf("UserRepo.ListUsersByEnrichmentStatus - r.Conn.Query: %v", err)
 	}
 	defer rows.Close()
 
 	var users []model.User
 	for rows.Next() {
 		user := model.User{}
-		err = rows.Scan(
-			&user.ID, &user.Name, &user.Surname, &user.Patronymic, &user.PassportNumber, &user.Address, &user.CreatedAt, &user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("UserRepo.ListUsersPagination - rows.Scan: %v", err)
+		if err := r.scanUser(rows, &user); err != nil {
+			return nil, fmt.Errorf("UserRepo.ListUsersByEnrichmentStatus - rows.Scan: %v", err)
 		}
 		users = append(users, user)
 	}
 	return users, nil
 }
 
+// ListUsersForSync returns enriched, not pinned, not deleted users with id greater than afterID
+// that were not synchronised with the People info API since syncedBefore.
+func (r *UserRepo) ListUsersForSync(ctx context.Context, syncedBefore time.Time, afterID, limit int) ([]model.User, error) {
+	sql, args, _ := r.Builder.Select(userColumns...).From("md.users").
+		Where(squirrel.Gt{"id": afterID}).
+		Where(squirrel.Eq{
+			"enrichment_status": model.EnrichmentStatusEnriched,
+			"profile_pinned":    false,
+			"deleted_at":        nil,
+			"anonymized_at":     nil,
+		}).
+		Where(squirrel.Or{squirrel.Eq{"synced_at": nil}, squirrel.Lt{"synced_at": syncedBefore}}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.ListUsersForSync - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user := model.User{}
+		if err := r.scanUser(rows, &user); err != nil {
+			return nil, fmt.Errorf("UserRepo.ListUsersForSync - rows.Scan: %v", err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// MarkUserSynced stamps synced_at without touching updated_at.
+func (r *UserRepo) MarkUserSynced(ctx context.Context, id int, syncedAt time.Time) error {
+	sql, args, _ := r.Builder.Update("md.users").Set("synced_at", syncedAt).Where("id = ?", id).ToSql()
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("UserRepo.MarkUserSynced - r.Conn.Exec: %v", err)
+	}
+	return nil
+}
+
 type UpdateUserInput struct {
 	Name           *string `json:"name"`
 	Surname        *string `json:"surname"`
 	Patronymic     *string `json:"patronymic"`
 	PassportNumber *string `json:"passport_number"`
 	Address        *string `json:"address"`
+	Email          *string `json:"email"`
+	// DocumentType and DocumentCountry are required with PassportNumber to compute the blind index
+	DocumentType    *string `json:"document_type"`
+	DocumentCountry *string `json:"document_country"`
+
+	EnrichmentStatus *string `json:"enrichment_status"`
+	ProfilePinned    *bool   `json:"profile_pinned"`
 }
 
 func (r *UserRepo) UpdateUser(ctx context.Context, ID int, data UpdateUserInput) error {
-	b := r.Builder.Update("md.users").Set("updated_at = ?", time.Now())
+	b := r.Builder.Update("md.users").Set("updated_at", time.Now())
 	if data.Name != nil {
 		b = b.Set("username", *data.Name)
 	}
@@ -162,29 +466,444 @@ func (r *UserRepo) UpdateUser(ctx context.Context, ID int, data UpdateUserInput)
 		b = b.Set("patronymic", *data.Patronymic)
 	}
 	if data.PassportNumber != nil {
-		b = b.Set("passport_number", *data.PassportNumber)
+		passportNumber, err := r.envelope.Encrypt(*data.PassportNumber)
+		if err != nil {
+			return fmt.Errorf("UserRepo.UpdateUser - r.envelope.Encrypt: %v", err)
+		}
+		doc := document.Document{Number: *data.PassportNumber}
+		if data.DocumentType != nil && data.DocumentCountry != nil {
+			doc.Type, doc.Country = *data.DocumentType, *data.DocumentCountry
+		}
+		b = b.Set("passport_number", passportNumber).
+			Set("passport_hash", r.documentHash(doc))
+	}
+	if data.DocumentType != nil {
+		b = b.Set("document_type", *data.DocumentType)
+	}
+	if data.DocumentCountry != nil {
+		b = b.Set("document_country", *data.DocumentCountry)
 	}
 	if data.Address != nil {
 		b = b.Set("address", *data.Address)
 	}
+	if data.Email != nil {
+		b = b.Set("email", *data.Email)
+	}
+	if data.EnrichmentStatus != nil {
+		b = b.Set("enrichment_status", *data.EnrichmentStatus)
+	}
+	if data.ProfilePinned != nil {
+		b = b.Set("profile_pinned", *data.ProfilePinned)
+	}
 	sql, args, _ := b.Where("id = ?", ID).ToSql()
 
-	_, err := r.Pool.Exec(ctx, sql, args...)
+	_, err := r.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("UserRepo.UpdateUser - r.Pool.Exec: %v", err)
+		return fmt.Errorf("UserRepo.UpdateUser - r.Conn.Exec: %v", err)
 	}
 
 	return nil
 }
 
+// DeleteUser marks the user as deleted, the row and its tasks are kept until PurgeUsers. The
+// model.EventUserDeleted outbox event is written along with it.
 func (r *UserRepo) DeleteUser(ctx context.Context, id int) error {
-	sql, args, err := r.Builder.Delete("md.users").Where("id = ?", id).ToSql()
+	sql, args, err := r.Builder.Update("md.users").
+		Set("deleted_at", time.Now()).
+		Where("id = ? AND deleted_at IS NULL", id).
+		ToSql()
 	if err != nil {
 		return fmt.Errorf("UserRepo.DeleteUser - r.Builder.ToSql: %v", err)
 	}
-	_, err = r.Pool.Exec(ctx, sql, args...)
+
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("UserRepo.DeleteUser - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("UserRepo.DeleteUser - r.Pool.Exec: %v", err)
+		return fmt.Errorf("UserRepo.DeleteUser - tx.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	err = r.addUserEvent(ctx, tx, id, model.EventUserDeleted)
+	if err != nil {
+		return fmt.Errorf("UserRepo.DeleteUser - r.addUserEvent: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("UserRepo.DeleteUser - tx.Commit: %v", err)
+	}
+	return nil
+}
+
+func (r *UserRepo) RestoreUser(ctx context.Context, id int) error {
+	sql, args, err := r.Builder.Update("md.users").
+		Set("deleted_at", nil).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("UserRepo.RestoreUser - r.Builder.ToSql: %v", err)
+	}
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("UserRepo.RestoreUser - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
 	}
 	return nil
 }
+
+// AnonymizeUser erases the personal data of the user, the row and its tasks stay for aggregate reports.
+func (r *UserRepo) AnonymizeUser(ctx context.Context, id int) error {
+	now := time.Now()
+	sql, args, err := r.Builder.Update("md.users").
+		SetMap(map[string]interface{}{
+			"username":        "",
+			"surname":         "",
+			"patronymic":      "",
+			"address":         "",
+			"email":           "",
+			"passport_number": "",
+			"passport_hash":   nil,
+			// there is nothing left to look up
+			"enrichment_status": squirrel.Expr("CASE WHEN enrichment_status = ? THEN ? ELSE enrichment_status END",
+				model.EnrichmentStatusPending, model.EnrichmentStatusFailed),
+			"anonymized_at": now,
+			"updated_at":    now,
+		}).
+		Where("id = ? AND anonymized_at IS NULL", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("UserRepo.AnonymizeUser - r.Builder.ToSql: %v", err)
+	}
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("UserRepo.AnonymizeUser - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeUsers permanently removes users soft deleted before the given time together with their tasks.
+func (r *UserRepo) PurgeUsers(ctx context.Context, deletedBefore time.Time) ([]int, error) {
+	// the tasks go with the user, a user with invoiced tasks is kept for the invoices
+	sql, args, err := r.Builder.Delete("md.users").
+		Where("deleted_at < ?", deletedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM md.tasks t WHERE t.user_id = md.users.id AND t.invoice_id IS NOT NULL)").
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.PurgeUsers - r.Builder.ToSql: %v", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.PurgeUsers - r.Conn.Query: %v", err)
+	}
+	IDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.PurgeUsers - pgx.CollectRows: %v", err)
+	}
+	return IDs, nil
+}
+
+// ReencryptPassportNumbers encrypts legacy plaintext passport numbers and rewraps the ones
+// encrypted with a retired key, the blind index is recomputed for every processed row.
+func (r *UserRepo) ReencryptPassportNumbers(ctx context.Context) (int, error) {
+	processed, err := r.reencryptPassportNumbers(ctx, false)
+	if err != nil {
+		return processed, fmt.Errorf("UserRepo.ReencryptPassportNumbers - %v", err)
+	}
+	return processed, nil
+}
+
+// BackfillPassportHashes encrypts the passport numbers written before the blind index was introduced and
+// computes their index, duplicate checks miss such rows until then.
+func (r *UserRepo) BackfillPassportHashes(ctx context.Context) (int, error) {
+	processed, err := r.reencryptPassportNumbers(ctx, true)
+	if err != nil {
+		return processed, fmt.Errorf("UserRepo.BackfillPassportHashes - %v", err)
+	}
+	return processed, nil
+}
+
+// reencryptPassportNumbers processes the rows that need rotation, or only the ones without the blind index.
+func (r *UserRepo) reencryptPassportNumbers(ctx context.Context, missingHashOnly bool) (int, error) {
+	type encryptedPassport struct {
+		ID              int
+		PassportNumber  string
+		DocumentType    string
+		DocumentCountry string
+	}
+
+	var processed, lastID int
+	for {
+		// anonymized users have no passport number left to encrypt
+		query := r.Builder.Select("id", "passport_number", "document_type", "document_country").From("md.users").
+			Where("id > ? AND passport_number <> ''", lastID).
+			OrderBy("id").
+			Limit(reencryptBatchSize)
+		if missingHashOnly {
+			query = query.Where("passport_hash IS NULL")
+		}
+		sql, args, _ := query.ToSql()
+
+		rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+		if err != nil {
+			return processed, fmt.Errorf("r.Conn.Query: %v", err)
+		}
+		batch, err := pgx.CollectRows(rows, pgx.RowToStructByPos[encryptedPassport])
+		if err != nil {
+			return processed, fmt.Errorf("pgx.CollectRows: %v", err)
+		}
+		if len(batch) == 0 {
+			return processed, nil
+		}
+
+		for _, row := range batch {
+			lastID = row.ID
+			if !missingHashOnly && !r.envelope.NeedsRotation(row.PassportNumber) {
+				continue
+			}
+
+			var passportNumber string
+			plaintext, err := r.envelope.Decrypt(row.PassportNumber)
+			switch {
+			case errors.Is(err, envelope.ErrNotEncrypted):
+				plaintext = row.PassportNumber
+				passportNumber, err = r.envelope.Encrypt(plaintext)
+			case err == nil:
+				passportNumber, err = r.envelope.Rewrap(row.PassportNumber)
+			}
+			if err != nil {
+				return processed, fmt.Errorf("r.envelope: %v", err)
+			}
+
+			sql, args, _ := r.Builder.Update("md.users").
+				Set("passport_number", passportNumber).
+				Set("passport_hash", r.documentHash(document.Document{
+					Type:    row.DocumentType,
+					Country: row.DocumentCountry,
+					Number:  plaintext,
+				})).
+				Where("id = ?", row.ID).
+				ToSql()
+			if _, err := r.Conn(ctx).Exec(ctx, sql, args...); err != nil {
+				return processed, fmt.Errorf("r.Conn.Exec: %v", err)
+			}
+			processed++
+		}
+	}
+}
+
+// weights of the field similarities in the duplicate score, they sum up to 1
+const (
+	duplicateSurnameWeight    = 0.35
+	duplicateNameWeight       = 0.25
+	duplicatePatronymicWeight = 0.2
+	duplicateAddressWeight    = 0.2
+)
+
+// FindDuplicateCandidates returns active users other than user whose weighted name, surname, patronymic and
+// address similarity reaches minScore, best matches first. Names are compared transliterated, see md.search_key.
+func (r *UserRepo) FindDuplicateCandidates(ctx context.Context, user model.User, minScore float64, limit int) ([]model.DuplicateCandidate, error) {
+	inner := r.Builder.Select(userColumns...).
+		Column(squirrel.Expr("similarity(md.search_key(username), md.search_key(?)) AS name_score", user.Name)).
+		Column(squirrel.Expr("similarity(md.search_key(surname), md.search_key(?)) AS surname_score", user.Surname)).
+		Column(squirrel.Expr("similarity(md.search_key(patronymic), md.search_key(?)) AS patronymic_score", user.Patronymic)).
+		Column(squirrel.Expr("similarity(lower(address), lower(?)) AS address_score", user.Address)).
+		From("md.users").
+		Where(squirrel.NotEq{"id": user.ID}).
+		Where(squirrel.Eq{"deleted_at": nil, "anonymized_at": nil}).
+		// the trigram indexes narrow the candidates down before scoring
+		Where(squirrel.Or{
+			squirrel.Expr("md.search_key(surname) % md.search_key(?)", user.Surname),
+			squirrel.Expr("md.search_key(username) % md.search_key(?)", user.Name),
+			squirrel.Expr("lower(address) % lower(?)", user.Address),
+		})
+
+	score := fmt.Sprintf("surname_score * %v + name_score * %v + patronymic_score * %v + address_score * %v",
+		duplicateSurnameWeight, duplicateNameWeight, duplicatePatronymicWeight, duplicateAddressWeight)
+	sql, args, err := r.Builder.Select("*").Column(score+" AS score").
+		FromSelect(inner, "candidates").
+		Where(score+" >= ?", minScore).
+		OrderBy("score DESC", "id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.FindDuplicateCandidates - r.Builder.ToSql: %v", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.FindDuplicateCandidates - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var candidates []model.DuplicateCandidate
+	for rows.Next() {
+		var c model.DuplicateCandidate
+		err := r.scanUser(rows, &c.User, &c.NameScore, &c.SurnameScore, &c.PatronymicScore, &c.AddressScore, &c.Score)
+		if err != nil {
+			return nil, fmt.Errorf("UserRepo.FindDuplicateCandidates - rows.Scan: %v", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// MergeUsers moves the tasks of the source user not invoiced yet to the target and soft deletes the source
+// pointing it to the target, all in one transaction with the model.EventUserDeleted outbox event of the source.
+// It returns the IDs of the moved tasks.
+func (r *UserRepo) MergeUsers(ctx context.Context, sourceID, targetID int) ([]int, error) {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.MergeUsers - r.Begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// keep the target from being deleted while the tasks move
+	sql, args, _ := r.Builder.Select("id").From("md.users").
+		Where("id = ? AND deleted_at IS NULL", targetID).
+		Suffix("FOR UPDATE").
+		ToSql()
+	var id int
+	err = tx.QueryRow(ctx, sql, args...).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrNotFound
+		}
+		return nil, fmt.Errorf("UserRepo.MergeUsers - tx.QueryRow: %v", err)
+	}
+
+	now := time.Now()
+	sql, args, _ = r.Builder.Update("md.users").
+		SetMap(map[string]interface{}{
+			"merged_into_id": targetID,
+			"deleted_at":     now,
+			"updated_at":     now,
+		}).
+		Where("id = ? AND deleted_at IS NULL", sourceID).
+		ToSql()
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.MergeUsers - tx.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, repoerr.ErrNotFound
+	}
+
+	// updated_at of a task is its completion time, so it is left as is. Invoiced tasks stay billed to the source.
+	sql, args, _ = r.Builder.Update("md.tasks").
+		Set("user_id", targetID).
+		Where("user_id = ? AND invoice_id IS NULL", sourceID).
+		Suffix("RETURNING id").
+		ToSql()
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.MergeUsers - tx.Query: %v", err)
+	}
+	taskIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.MergeUsers - pgx.CollectRows: %v", err)
+	}
+	err = r.addUserEvent(ctx, tx, sourceID, model.EventUserDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.MergeUsers - r.addUserEvent: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("UserRepo.MergeUsers - tx.Commit: %v", err)
+	}
+	return taskIDs, nil
+}
+
+// SetCalendarToken replaces the calendar feed token of the user, only its blind index is stored.
+func (r *UserRepo) SetCalendarToken(ctx context.Context, id int, token string) error {
+	sql, args, _ := r.Builder.Update("md.users").
+		Set("calendar_token_hash", r.envelope.BlindIndex("calendar:"+token)).
+		Where("id = ? AND deleted_at IS NULL", id).
+		ToSql()
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("UserRepo.SetCalendarToken - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
+
+// CheckCalendarToken reports whether token is the current calendar feed token of the user.
+func (r *UserRepo) CheckCalendarToken(ctx context.Context, id int, token string) (bool, error) {
+	sql, args, _ := r.Builder.Select().
+		Column(squirrel.Expr("COALESCE(calendar_token_hash = ?, false)", r.envelope.BlindIndex("calendar:"+token))).
+		From("md.users").
+		Where("id = ? AND deleted_at IS NULL", id).
+		ToSql()
+
+	var valid bool
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&valid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("UserRepo.CheckCalendarToken - r.Conn.QueryRow: %v", err)
+	}
+	return valid, nil
+}
+
+// FindUsersByEmailOrName finds users by the email when it is given and matches, otherwise by the full name in
+// either "name surname" or "surname name" order compared transliterated, see md.search_key. Soft deleted users
+// are skipped, at most limit users are returned.
+func (r *UserRepo) FindUsersByEmailOrName(ctx context.Context, email, fullName string, limit int) ([]model.User, error) {
+	if email != "" {
+		users, err := r.findUsers(ctx, squirrel.Expr("email <> '' AND lower(email) = lower(?)", email), limit)
+		if err != nil || len(users) > 0 || fullName == "" {
+			return users, err
+		}
+	}
+	if fullName == "" {
+		return nil, nil
+	}
+	return r.findUsers(ctx, squirrel.Expr(
+		"(md.search_key(username || ' ' || surname) = md.search_key(?) OR md.search_key(surname || ' ' || username) = md.search_key(?))",
+		fullName, fullName,
+	), limit)
+}
+
+func (r *UserRepo) findUsers(ctx context.Context, match squirrel.Sqlizer, limit int) ([]model.User, error) {
+	sql, args, _ := r.Builder.Select(userColumns...).
+		From("md.users").
+		Where(match).
+		Where("deleted_at IS NULL").
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.FindUsersByEmailOrName - r.Conn.Query: %v", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var user model.User
+		if err := r.scanUser(rows, &user); err != nil {
+			return nil, fmt.Errorf("UserRepo.FindUsersByEmailOrName - rows.Scan: %v", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("UserRepo.FindUsersByEmailOrName - rows.Err: %v", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,52 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CreateUserStatusChangeInput struct {
+	UserID        int
+	Status        string
+	EffectiveFrom time.Time
+	Reason        string
+}
+
+func (r *UserRepo) CreateUserStatusChange(ctx context.Context, data CreateUserStatusChangeInput) (int, error) {
+	var ID int
+	sql, args, _ := r.Builder.Insert("md.user_statuses").
+		Columns("user_id", "status", "effective_from", "reason", "created_at").
+		Values(data.UserID, data.Status, data.EffectiveFrom, data.Reason, time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		return 0, fmt.Errorf("UserRepo.CreateUserStatusChange - r.Conn.QueryRow: %v", err)
+	}
+	return ID, nil
+}
+
+// ListUserStatusChanges returns the lifecycle history of the user in the order the changes take effect,
+// scheduled ones included.
+func (r *UserRepo) ListUserStatusChanges(ctx context.Context, userID int) ([]model.UserStatusChange, error) {
+	sql, args, _ := r.Builder.Select("id", "user_id", "status", "effective_from", "reason", "created_at").
+		From("md.user_statuses").
+		Where("user_id = ?", userID).
+		OrderBy("effective_from", "id").
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.ListUserStatusChanges - r.Conn.Query: %v", err)
+	}
+	changes, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.UserStatusChange])
+	if err != nil {
+		return nil, fmt.Errorf("UserRepo.ListUserStatusChanges - pgx.CollectRows: %v", err)
+	}
+	return changes, nil
+}
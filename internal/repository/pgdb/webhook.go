@@ -0,0 +1,243 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/postgres"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	webhookSubscriptionColumns = []string{"id", "url", "events", "secret", "created_at"}
+	webhookDeliveryColumns     = []string{
+		"id", "subscription_id", "event_id", "event", "payload", "status", "attempts", "next_attempt_at",
+		"response_status", "last_error", "created_at", "delivered_at",
+	}
+)
+
+type WebhookRepo struct {
+	*postgres.Postgres
+}
+
+func NewWebhookRepo(db *postgres.Postgres) *WebhookRepo {
+	return &WebhookRepo{db}
+}
+
+type CreateWebhookSubscriptionInput struct {
+	URL    string
+	Events []string
+	Secret string
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, data CreateWebhookSubscriptionInput) (int, error) {
+	events := data.Events
+	if events == nil {
+		events = []string{}
+	}
+	sql, args, _ := r.Builder.Insert("md.webhook_subscriptions").
+		Columns("url", "events", "secret", "created_at").
+		Values(data.URL, events, data.Secret, time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+
+	var ID int
+	err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&ID)
+	if err != nil {
+		return 0, fmt.Errorf("WebhookRepo.CreateSubscription - r.Conn.QueryRow: %v", err)
+	}
+	return ID, nil
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, ID int) (model.WebhookSubscription, error) {
+	sql, args, _ := r.Builder.Select(webhookSubscriptionColumns...).From("md.webhook_subscriptions").Where("id = ?", ID).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return model.WebhookSubscription{}, fmt.Errorf("WebhookRepo.GetSubscription - r.Conn.Query: %v", err)
+	}
+	subscription, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[model.WebhookSubscription])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscription, repoerr.ErrNotFound
+		}
+		return subscription, fmt.Errorf("WebhookRepo.GetSubscription - pgx.CollectOneRow: %v", err)
+	}
+	return subscription, nil
+}
+
+// ListSubscriptions returns every subscription when event is empty, otherwise the ones receiving the event.
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context, event string) ([]model.WebhookSubscription, error) {
+	b := r.Builder.Select(webhookSubscriptionColumns...).From("md.webhook_subscriptions").OrderBy("id")
+	if event != "" {
+		b = b.Where("(cardinality(events) = 0 OR ? = ANY(events))", event)
+	}
+	sql, args, _ := b.ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.ListSubscriptions - r.Conn.Query: %v", err)
+	}
+	subscriptions, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookSubscription])
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.ListSubscriptions - pgx.CollectRows: %v", err)
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes the subscription with its deliveries.
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, ID int) error {
+	sql, args, _ := r.Builder.Delete("md.webhook_subscriptions").Where("id = ?", ID).ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("WebhookRepo.DeleteSubscription - r.Conn.Exec: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
+
+type CreateWebhookDeliveriesInput struct {
+	SubscriptionIDs []int
+	EventID         string
+	Event           string
+	Payload         []byte
+}
+
+// CreateDeliveries queues the payload for every subscription, due at once.
+func (r *WebhookRepo) CreateDeliveries(ctx context.Context, data CreateWebhookDeliveriesInput) ([]int, error) {
+	now := time.Now()
+	b := r.Builder.Insert("md.webhook_deliveries").
+		Columns("subscription_id", "event_id", "event", "payload", "status", "next_attempt_at", "created_at")
+	for _, subscriptionID := range data.SubscriptionIDs {
+		b = b.Values(subscriptionID, data.EventID, data.Event, string(data.Payload), model.WebhookDeliveryPending, now, now)
+	}
+	sql, args, _ := b.Suffix("RETURNING id").ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.CreateDeliveries - r.Conn.Query: %v", err)
+	}
+	IDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.CreateDeliveries - pgx.CollectRows: %v", err)
+	}
+	return IDs, nil
+}
+
+func (r *WebhookRepo) GetDelivery(ctx context.Context, ID int) (model.WebhookDelivery, error) {
+	sql, args, _ := r.Builder.Select(webhookDeliveryColumns...).From("md.webhook_deliveries").Where("id = ?", ID).ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("WebhookRepo.GetDelivery - r.Conn.Query: %v", err)
+	}
+	delivery, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[model.WebhookDelivery])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return delivery, repoerr.ErrNotFound
+		}
+		return delivery, fmt.Errorf("WebhookRepo.GetDelivery - pgx.CollectOneRow: %v", err)
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns the deliveries to the subscription, newest first.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	sql, args, _ := r.Builder.Select(webhookDeliveryColumns...).
+		From("md.webhook_deliveries").
+		Where("subscription_id = ?", subscriptionID).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.ListDeliveries - r.Conn.Query: %v", err)
+	}
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookDelivery])
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.ListDeliveries - pgx.CollectRows: %v", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries returns the pending deliveries due by now, oldest first, and postpones them by lease, so that
+// other instances skip them meanwhile and a delivery interrupted by a crash is retried once the lease expires.
+func (r *WebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	now := time.Now()
+	// the outer statement numbers the placeholders of the subquery
+	due, dueArgs, _ := squirrel.Select("id").
+		From("md.webhook_deliveries").
+		Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		OrderBy("next_attempt_at", "id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	sql, args, _ := r.Builder.Update("md.webhook_deliveries").
+		Set("next_attempt_at", now.Add(lease)).
+		Where("id IN ("+due+")", dueArgs...).
+		Suffix("RETURNING " + strings.Join(webhookDeliveryColumns, ", ")).
+		ToSql()
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.ClaimDueDeliveries - r.Conn.Query: %v", err)
+	}
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookDelivery])
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepo.ClaimDueDeliveries - pgx.CollectRows: %v", err)
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDeliveryInput is the outcome of a delivery attempt, NextAttemptAt is nil once the delivery is
+// delivered or failed.
+type UpdateWebhookDeliveryInput struct {
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      string
+	DeliveredAt    *time.Time
+}
+
+func (r *WebhookRepo) UpdateDelivery(ctx context.Context, ID int, data UpdateWebhookDeliveryInput) error {
+	sql, args, _ := r.Builder.Update("md.webhook_deliveries").
+		Set("status", data.Status).
+		Set("attempts", data.Attempts).
+		Set("next_attempt_at", data.NextAttemptAt).
+		Set("response_status", data.ResponseStatus).
+		Set("last_error", data.LastError).
+		Set("delivered_at", data.DeliveredAt).
+		Where("id = ?", ID).
+		ToSql()
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("WebhookRepo.UpdateDelivery - r.Conn.Exec: %v", err)
+	}
+	return nil
+}
+
+// PurgeDeliveries removes the delivered and failed deliveries created before the given time and returns their
+// number.
+func (r *WebhookRepo) PurgeDeliveries(ctx context.Context, createdBefore time.Time) (int64, error) {
+	sql, args, _ := r.Builder.Delete("md.webhook_deliveries").
+		Where("status <> ? AND created_at < ?", model.WebhookDeliveryPending, createdBefore).
+		ToSql()
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("WebhookRepo.PurgeDeliveries - r.Conn.Exec: %v", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -2,35 +2,147 @@ package repository
 
 import (
 	"context"
+	"time"
 	"time-tracker/internal/model"
 	"time-tracker/internal/repository/pgdb"
+	"time-tracker/pkg/document"
+	"time-tracker/pkg/envelope"
 	"time-tracker/pkg/postgres"
 )
 
+// Transactor runs fn in a transaction, the repositories called with the ctx of fn join it.
+type Transactor interface{
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type User interface{
 	CreateUser(ctx context.Context, data pgdb.CreateUserInput) (int, error)
-	GetUser(ctx context.Context, ID int) (model.User, error)
-	GeUsertByPassportNumber(ctx context.Context, passportNumber string) (model.User, error)
-	ListUsersPagination(ctx context.Context, name, surname, patronymic, passport_number, address string, limit, offset int) ([]model.User, error)
+	GetUser(ctx context.Context, ID int, includeDeleted bool) (model.User, error)
+	GeUsertByPassportNumber(ctx context.Context, doc document.Document) (model.User, error)
+	ListUsersPagination(ctx context.Context, filter pgdb.ListUsersFilter) (pgdb.Page[model.User], error)
+	ForEachUser(ctx context.Context, filter pgdb.ListUsersFilter, fn func(model.User) error) error
+	ListUsersByEnrichmentStatus(ctx context.Context, status string, limit int) ([]model.User, error)
+	ListUsersForSync(ctx context.Context, syncedBefore time.Time, afterID, limit int) ([]model.User, error)
+	MarkUserSynced(ctx context.Context, id int, syncedAt time.Time) error
 	UpdateUser(ctx context.Context, ID int, data pgdb.UpdateUserInput) error
 	DeleteUser(ctx context.Context, id int) error
+	RestoreUser(ctx context.Context, id int) error
+	AnonymizeUser(ctx context.Context, id int) error
+	PurgeUsers(ctx context.Context, deletedBefore time.Time) ([]int, error)
+	ReencryptPassportNumbers(ctx context.Context) (int, error)
+	BackfillPassportHashes(ctx context.Context) (int, error)
+	FindDuplicateCandidates(ctx context.Context, user model.User, minScore float64, limit int) ([]model.DuplicateCandidate, error)
+	MergeUsers(ctx context.Context, sourceID, targetID int) ([]int, error)
+	CreateUserStatusChange(ctx context.Context, data pgdb.CreateUserStatusChangeInput) (int, error)
+	ListUserStatusChanges(ctx context.Context, userID int) ([]model.UserStatusChange, error)
+	SetCalendarToken(ctx context.Context, id int, token string) error
+	CheckCalendarToken(ctx context.Context, id int, token string) (bool, error)
+	FindUsersByEmailOrName(ctx context.Context, email, fullName string, limit int) ([]model.User, error)
 }
 
 type Task interface{
 	CreateTask(ctx context.Context, data pgdb.CreateTaskInput) (int, error)
-	GetTask(ctx context.Context, ID int) (model.Task, error)
-	ListTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter) ([]model.Task, error)
+	ImportTask(ctx context.Context, data pgdb.ImportTaskInput) (int, error)
+	GetTaskIDsByExternalID(ctx context.Context, externalIDs []string) (map[string]int, error)
+	GetTask(ctx context.Context, ID int, includeDeleted bool) (model.Task, error)
+	ListTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter) (pgdb.Page[model.Task], error)
+	ForEachTask(ctx context.Context, userID int, filter pgdb.ListTasksFilter, fn func(model.Task) error) error
 	UpdateTask(ctx context.Context, ID int, data pgdb.UpdateTaskInput) error
+	DeleteTask(ctx context.Context, ID int) error
+	RestoreTask(ctx context.Context, ID int) error
+	PurgeTasks(ctx context.Context, deletedBefore time.Time) ([]int, error)
+}
+
+type Project interface{
+	CreateProject(ctx context.Context, data pgdb.CreateProjectInput) (int, error)
+	GetProject(ctx context.Context, ID int) (model.Project, error)
+	GetProjectByName(ctx context.Context, name string) (model.Project, error)
+	ListProjects(ctx context.Context) ([]model.Project, error)
+	SetProjectClient(ctx context.Context, ID int, clientID *int) error
+}
+
+type Client interface{
+	CreateClient(ctx context.Context, data pgdb.CreateClientInput) (int, error)
+	GetClient(ctx context.Context, ID int) (model.Client, error)
+	ListClients(ctx context.Context) ([]model.Client, error)
+	UpdateClient(ctx context.Context, ID int, data pgdb.UpdateClientInput) error
+}
+
+type Budget interface{
+	SetBudget(ctx context.Context, data pgdb.SetBudgetInput) error
+	GetBudget(ctx context.Context, projectID int) (model.ProjectBudget, error)
+	DeleteBudget(ctx context.Context, projectID int) error
+	ProjectDuration(ctx context.Context, projectID int, from, to time.Time) (int64, error)
+	ProjectDailyDurations(ctx context.Context, projectID int, from, to time.Time, timezone string) ([]pgdb.DailyDuration, error)
+	FirstProjectTaskTime(ctx context.Context, projectID int) (time.Time, error)
+	CreateBudgetAlert(ctx context.Context, alert model.BudgetAlert) (int, error)
+	ListPendingBudgetAlerts(ctx context.Context, limit int) ([]model.BudgetAlert, error)
+	MarkBudgetAlertNotified(ctx context.Context, ID int, notifiedAt time.Time) error
+}
+
+type Invoice interface{
+	ListBillableTasks(ctx context.Context, filter pgdb.BillableTasksFilter) ([]model.Task, error)
+	CreateInvoice(ctx context.Context, data pgdb.CreateInvoiceInput) (int, error)
+	GetInvoice(ctx context.Context, ID int) (model.Invoice, error)
+}
+
+type Webhook interface{
+	CreateSubscription(ctx context.Context, data pgdb.CreateWebhookSubscriptionInput) (int, error)
+	GetSubscription(ctx context.Context, ID int) (model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, event string) ([]model.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, ID int) error
+	CreateDeliveries(ctx context.Context, data pgdb.CreateWebhookDeliveriesInput) ([]int, error)
+	GetDelivery(ctx context.Context, ID int) (model.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]model.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, ID int, data pgdb.UpdateWebhookDeliveryInput) error
+	PurgeDeliveries(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+type Outbox interface{
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	UpdateOutboxEvent(ctx context.Context, ID int64, data pgdb.UpdateOutboxEventInput) error
+	PurgeOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+type Audit interface{
+	CreateRecord(ctx context.Context, data pgdb.CreateAuditRecordInput) (int, error)
+	ListRecords(ctx context.Context, filter pgdb.ListAuditRecordsFilter) ([]model.AuditRecord, error)
+	ListEntityRecords(ctx context.Context, entity string, entityIDs []int) ([]model.AuditRecord, error)
+	ScrubRecords(ctx context.Context, entity string, entityID int, replacement string) error
+}
+
+type Report interface{
+	ForEachWorklogEntry(ctx context.Context, filter pgdb.WorklogFilter, fn func(model.WorklogEntry) error) error
+	ForEachClientWorklogEntry(ctx context.Context, filter pgdb.WorklogFilter, fn func(model.ClientWorklogEntry) error) error
 }
 
 type Repositories struct {
 	User
 	Task
+	Project
+	Client
+	Budget
+	Invoice
+	Webhook
+	Outbox
+	Audit
+	Report
+	Transactor
 }
 
-func NewRepositories(db *postgres.Postgres) *Repositories {
+func NewRepositories(db *postgres.Postgres, envelope *envelope.Envelope, maxPageLimit int) *Repositories {
 	return &Repositories{
-		User: pgdb.NewUserRepo(db),
-		Task: pgdb.NewTaskRepo(db),
+		User: pgdb.NewUserRepo(db, envelope, maxPageLimit),
+		Task: pgdb.NewTaskRepo(db, maxPageLimit),
+		Project: pgdb.NewProjectRepo(db),
+		Client: pgdb.NewClientRepo(db),
+		Budget: pgdb.NewBudgetRepo(db),
+		Invoice: pgdb.NewInvoiceRepo(db),
+		Webhook: pgdb.NewWebhookRepo(db),
+		Outbox: pgdb.NewOutboxRepo(db),
+		Audit: pgdb.NewAuditRepo(db, maxPageLimit),
+		Report: pgdb.NewReportRepo(db),
+		Transactor: db,
 	}
 }
\ No newline at end of file
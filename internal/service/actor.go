@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"strconv"
+)
+
+const (
+	SystemActor    = "system"
+	AnonymousActor = "anonymous"
+)
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the identity of whoever performs the mutation.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// UserActor is the actor the gateway sets for requests made by the tracker user itself.
+func UserActor(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// ActorFromContext returns the actor stored in ctx, background jobs fall back to SystemActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+const (
+	// PermissionAdmin allows to see and manage soft deleted entities.
+	PermissionAdmin = "admin"
+	// PermissionPIIRead allows to see personal data such as passport numbers and addresses unmasked.
+	PermissionPIIRead = "pii:read"
+)
+
+type permissionsKey struct{}
+
+// WithPermissions returns a copy of ctx carrying the permissions granted to the actor.
+func WithPermissions(ctx context.Context, permissions []string) context.Context {
+	return context.WithValue(ctx, permissionsKey{}, permissions)
+}
+
+func HasPermission(ctx context.Context, permission string) bool {
+	permissions, _ := ctx.Value(permissionsKey{}).([]string)
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUserOrAdmin tells whether the request is made by the user itself or by an admin.
+func IsUserOrAdmin(ctx context.Context, userID int) bool {
+	return ActorFromContext(ctx) == UserActor(userID) || HasPermission(ctx, PermissionAdmin)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+)
+
+// AuditRedacted replaces values of encrypted fields in audit diffs.
+const AuditRedacted = "[redacted]"
+
+// fields that change on every mutation and only add noise to the diff
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// fields stored encrypted at rest, the diff only tells that they changed
+var auditRedactedFields = map[string]bool{
+	"passport_number": true,
+}
+
+type AuditService struct {
+	repo repository.Audit
+}
+
+func NewAuditService(repo repository.Audit) *AuditService {
+	return &AuditService{repo}
+}
+
+// Record stores the mutation of an entity, before or after is nil for creations and deletions.
+func (s *AuditService) Record(ctx context.Context, entity string, entityID int, action string, before, after any) error {
+	diff, err := auditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("AuditService.Record - auditDiff: %v", err)
+	}
+
+	_, err = s.repo.CreateRecord(ctx, pgdb.CreateAuditRecordInput{
+		Actor:    ActorFromContext(ctx),
+		Entity:   entity,
+		EntityID: entityID,
+		Action:   action,
+		Diff:     diff,
+	})
+	return err
+}
+
+func (s *AuditService) ListRecords(ctx context.Context, filter pgdb.ListAuditRecordsFilter) ([]model.AuditRecord, error) {
+	return s.repo.ListRecords(ctx, filter)
+}
+
+func (s *AuditService) ListEntityRecords(ctx context.Context, entity string, entityIDs []int) ([]model.AuditRecord, error) {
+	return s.repo.ListEntityRecords(ctx, entity, entityIDs)
+}
+
+// Scrub erases the values recorded for an entity, used when its personal data is anonymized.
+func (s *AuditService) Scrub(ctx context.Context, entity string, entityID int) error {
+	return s.repo.ScrubRecords(ctx, entity, entityID, AuditRedacted)
+}
+
+type auditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+func auditDiff(before, after any) ([]byte, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]auditChange)
+	for field, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[field]) {
+			changes[field] = auditChange{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = auditChange{After: value}
+		}
+	}
+	for field, change := range changes {
+		if auditRedactedFields[field] {
+			changes[field] = auditChange{Before: redact(change.Before), After: redact(change.After)}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+func redact(value any) any {
+	if value == nil {
+		return nil
+	}
+	return AuditRedacted
+}
+
+func auditFields(entity any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if entity == nil {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for field := range auditIgnoredFields {
+		delete(fields, field)
+	}
+	return fields, nil
+}
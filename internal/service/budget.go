@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/notify"
+)
+
+// EventBudgetThreshold is the notification event of budget alerts.
+const EventBudgetThreshold = "budget.threshold"
+
+type BudgetService struct {
+	repo       repository.Budget
+	projects   repository.Project
+	clients    repository.Client
+	tx         repository.Transactor
+	audit      Audit
+	notifier   Notifier
+	thresholds []int // percent
+	location   *time.Location
+	batchSize  int
+}
+
+func NewBudgetService(repo repository.Budget, projects repository.Project, clients repository.Client, tx repository.Transactor, audit Audit, notifier Notifier, thresholds []int, location *time.Location, batchSize int) *BudgetService {
+	return &BudgetService{repo, projects, clients, tx, audit, notifier, thresholds, location, batchSize}
+}
+
+// SetBudgetInput limits the project, Limit is in hours for hours budgets and in minor currency units for money
+// budgets.
+type SetBudgetInput struct {
+	Kind   string
+	Period string
+	Limit  float64
+	Rate   int64 // per hour, money budgets only, the default rate of the project's client when zero
+}
+
+// SetBudget creates or replaces the budget of the project, a limit the project already exceeds alerts at once.
+func (s *BudgetService) SetBudget(ctx context.Context, projectID int, input SetBudgetInput) error {
+	project, err := s.projects.GetProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	data := pgdb.SetBudgetInput{ProjectID: projectID, Kind: input.Kind, Period: input.Period}
+	switch input.Kind {
+	case model.BudgetKindHours:
+		data.Limit = int64(input.Limit * 60)
+	case model.BudgetKindMoney:
+		data.Limit, data.Rate = int64(input.Limit), input.Rate
+		if data.Rate == 0 && project.ClientID != nil {
+			client, err := s.clients.GetClient(ctx, *project.ClientID)
+			if err != nil {
+				return err
+			}
+			data.Rate = client.DefaultRate
+		}
+		if data.Rate == 0 {
+			return ErrRateRequired
+		}
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var before any
+		budget, err := s.repo.GetBudget(ctx, projectID)
+		switch {
+		case err == nil:
+			before = budget
+		case !errors.Is(err, repoerr.ErrNotFound):
+			return err
+		}
+		err = s.repo.SetBudget(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetBudget(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		err = s.audit.Record(ctx, model.AuditEntityProject, projectID, model.AuditActionBudget, before, after)
+		if err != nil {
+			return err
+		}
+		return s.CheckBudget(ctx, projectID, time.Now())
+	})
+}
+
+func (s *BudgetService) GetBudget(ctx context.Context, projectID int) (model.ProjectBudget, error) {
+	return s.repo.GetBudget(ctx, projectID)
+}
+
+func (s *BudgetService) DeleteBudget(ctx context.Context, projectID int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetBudget(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		err = s.repo.DeleteBudget(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityProject, projectID, model.AuditActionBudget, before, nil)
+	})
+}
+
+// CheckBudget raises the alerts for the thresholds the consumption of the budget period containing at crossed
+// since the last check. Projects without a budget are skipped. The alerts are only stored, pending for
+// NotifyPendingAlerts, so that the check can run in the transaction of the change and completing tasks doesn't
+// depend on the notifier.
+func (s *BudgetService) CheckBudget(ctx context.Context, projectID int, at time.Time) error {
+	budget, err := s.repo.GetBudget(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	start, end, key := budgetPeriod(budget.Period, at.In(s.location))
+	duration, err := s.repo.ProjectDuration(ctx, projectID, start, end)
+	if err != nil {
+		return err
+	}
+	consumed := budgetConsumption(budget, duration)
+
+	for _, threshold := range s.thresholds {
+		if consumed*100 < budget.Limit*int64(threshold) {
+			continue
+		}
+		_, err := s.repo.CreateBudgetAlert(ctx, model.BudgetAlert{
+			ProjectID: projectID,
+			PeriodKey: key,
+			Threshold: threshold,
+			Consumed:  consumed,
+		})
+		if err != nil && !errors.Is(err, repoerr.ErrAlreadyExists) {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyPendingAlerts delivers the alerts raised by CheckBudget, the ones the notifier fails to deliver stay
+// pending for the next run.
+func (s *BudgetService) NotifyPendingAlerts(ctx context.Context) error {
+	alerts, err := s.repo.ListPendingBudgetAlerts(ctx, s.batchSize)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, alert := range alerts {
+		budget, err := s.repo.GetBudget(ctx, alert.ProjectID)
+		if err == nil {
+			err = s.notify(ctx, budget, alert)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("budget alert %d: %w", alert.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// budgetAlertData is the data of budget alert notifications.
+type budgetAlertData struct {
+	model.BudgetAlert
+	ProjectName string `json:"project_name"`
+	Kind        string `json:"kind"`
+	Period      string `json:"period"`
+	Limit       int64  `json:"limit"`
+}
+
+func (s *BudgetService) notify(ctx context.Context, budget model.ProjectBudget, alert model.BudgetAlert) error {
+	project, err := s.projects.GetProject(ctx, alert.ProjectID)
+	if err != nil {
+		return err
+	}
+	err = s.notifier.Notify(ctx, notify.Notification{
+		Event: EventBudgetThreshold,
+		Text: fmt.Sprintf("Проект «%s»: израсходовано %d%% бюджета (%s из %s)", project.Name, alert.Threshold,
+			formatBudgetAmount(budget.Kind, alert.Consumed), formatBudgetAmount(budget.Kind, budget.Limit)),
+		Data: budgetAlertData{
+			BudgetAlert: alert,
+			ProjectName: project.Name,
+			Kind:        budget.Kind,
+			Period:      budget.Period,
+			Limit:       budget.Limit,
+		},
+		Time: alert.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return s.repo.MarkBudgetAlertNotified(ctx, alert.ID, time.Now())
+}
+
+func formatBudgetAmount(kind string, amount int64) string {
+	if kind == model.BudgetKindHours {
+		return strings.Replace(strconv.FormatFloat(float64(amount)/60, 'f', 1, 64), ".", ",", 1) + " ч"
+	}
+	return fmt.Sprintf("%d,%02d", amount/100, amount%100)
+}
+
+// Burndown reports the consumption of the budget period containing at day by day up to the day of at.
+func (s *BudgetService) Burndown(ctx context.Context, projectID int, at time.Time) (model.BudgetBurndown, error) {
+	budget, err := s.repo.GetBudget(ctx, projectID)
+	if err != nil {
+		return model.BudgetBurndown{}, err
+	}
+	at = at.In(s.location)
+	start, end, key := budgetPeriod(budget.Period, at)
+	lifetime := budget.Period == model.BudgetPeriodLifetime
+	if lifetime {
+		first, err := s.repo.FirstProjectTaskTime(ctx, projectID)
+		switch {
+		case err == nil:
+			start = day(first.In(s.location))
+		case errors.Is(err, repoerr.ErrNotFound):
+			start = day(at)
+		default:
+			return model.BudgetBurndown{}, err
+		}
+		end = day(at).AddDate(0, 0, 1)
+	}
+
+	durations, err := s.repo.ProjectDailyDurations(ctx, projectID, start, end, s.location.String())
+	if err != nil {
+		return model.BudgetBurndown{}, err
+	}
+	daily := make(map[string]int64, len(durations))
+	for _, d := range durations {
+		daily[d.Date.Format(time.DateOnly)] = d.Duration
+	}
+
+	burndown := model.BudgetBurndown{
+		Budget:      budget,
+		PeriodKey:   key,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Points:      []model.BurndownPoint{},
+	}
+	days := 0
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	var duration int64
+	i := 0
+	for d := start; d.Before(end) && !d.After(at); d = d.AddDate(0, 0, 1) {
+		i++
+		duration += daily[d.Format(time.DateOnly)]
+		consumed := budgetConsumption(budget, duration)
+		point := model.BurndownPoint{Date: d, Consumed: consumed, Remaining: budget.Limit - consumed}
+		if !lifetime {
+			ideal := budget.Limit - budget.Limit*int64(i)/int64(days)
+			point.Ideal = &ideal
+		}
+		burndown.Points = append(burndown.Points, point)
+	}
+	burndown.Consumed = budgetConsumption(budget, duration)
+	burndown.Remaining = budget.Limit - burndown.Consumed
+	return burndown, nil
+}
+
+// budgetConsumption converts the minutes tracked to the units of the budget limit.
+func budgetConsumption(budget model.ProjectBudget, duration int64) int64 {
+	if budget.Kind == model.BudgetKindMoney {
+		return roundDiv(duration*budget.Rate, 60)
+	}
+	return duration
+}
+
+// budgetPeriod returns the bounds of the period containing t, in the location of t, and the key alerts are
+// deduplicated by. Weeks start on Monday and are keyed by the ISO week. Lifetime has zero bounds.
+func budgetPeriod(period string, t time.Time) (time.Time, time.Time, string) {
+	switch period {
+	case model.BudgetPeriodWeek:
+		start := day(t).AddDate(0, 0, -(int(t.Weekday())+6)%7)
+		year, week := t.ISOWeek()
+		return start, start.AddDate(0, 0, 7), fmt.Sprintf("%d-W%02d", year, week)
+	case model.BudgetPeriodMonth:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0), start.Format("2006-01")
+	case model.BudgetPeriodQuarter:
+		quarter := (int(t.Month()) - 1) / 3
+		start := time.Date(t.Year(), time.Month(quarter*3+1), 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 3, 0), fmt.Sprintf("%d-Q%d", t.Year(), quarter+1)
+	case model.BudgetPeriodYear:
+		start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(1, 0, 0), strconv.Itoa(t.Year())
+	default:
+		return time.Time{}, time.Time{}, model.BudgetPeriodLifetime
+	}
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
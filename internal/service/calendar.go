@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/pkg/ical"
+)
+
+const calendarTokenBytes = 32
+
+type CalendarService struct {
+	users     repository.User
+	tasks     repository.Task
+	tx        repository.Transactor
+	audit     Audit
+	window    time.Duration
+	maxWindow time.Duration
+	location  *time.Location
+}
+
+func NewCalendarService(users repository.User, tasks repository.Task, tx repository.Transactor, audit Audit, window, maxWindow time.Duration, location *time.Location) *CalendarService {
+	return &CalendarService{users, tasks, tx, audit, window, maxWindow, location}
+}
+
+// CalendarFeed is the tracked time of a user as calendar events in the feed time zone.
+type CalendarFeed struct {
+	Name     string
+	Location *time.Location
+	From     time.Time
+	To       time.Time
+	Events   []ical.Event
+}
+
+// IssueCalendarToken generates a new token for the calendar feed of the user, the previous one stops working.
+// Calendar apps can not send the gateway headers, so the feed is protected by the token in its URL. Only the user
+// or an admin may issue it.
+func (s *CalendarService) IssueCalendarToken(ctx context.Context, userID int) (string, error) {
+	if !IsUserOrAdmin(ctx, userID) {
+		return "", ErrForbidden
+	}
+	raw := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("CalendarService.IssueCalendarToken - rand.Read: %v", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.users.SetCalendarToken(ctx, userID, token)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityUser, userID, model.AuditActionCalendarToken, nil, nil)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CalendarFeed returns the tasks of the user started within window before now, a zero window is the default one.
+func (s *CalendarService) CalendarFeed(ctx context.Context, userID int, token string, window time.Duration) (CalendarFeed, error) {
+	valid, err := s.users.CheckCalendarToken(ctx, userID, token)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+	if !valid {
+		return CalendarFeed{}, ErrInvalidToken
+	}
+	user, err := s.users.GetUser(ctx, userID, false)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+
+	if window <= 0 {
+		window = s.window
+	}
+	if window > s.maxWindow {
+		window = s.maxWindow
+	}
+	now := time.Now()
+	feed := CalendarFeed{
+		Name:     strings.TrimSpace(user.Surname + " " + user.Name),
+		Location: s.location,
+		From:     now.Add(-window),
+		To:       now,
+	}
+
+	filter := pgdb.ListTasksFilter{DateFrom: feed.From, PageRequest: pgdb.PageRequest{Sort: "created_at"}}
+	err = s.tasks.ForEachTask(ctx, userID, filter, func(task model.Task) error {
+		feed.Events = append(feed.Events, taskEvent(task, now))
+		return nil
+	})
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+	return feed, nil
+}
+
+// taskEvent spans the task from its start to its completion, tasks in progress end now.
+func taskEvent(task model.Task, now time.Time) ical.Event {
+	end := now
+	modified := task.CreatedAt
+	if task.UpdatedAt != nil {
+		modified = *task.UpdatedAt
+	}
+	if task.Completed {
+		end = task.CreatedAt.Add(time.Duration(task.Duration) * time.Minute)
+		if task.UpdatedAt != nil {
+			end = *task.UpdatedAt
+		}
+	}
+	if end.Before(task.CreatedAt) {
+		end = task.CreatedAt
+	}
+
+	return ical.Event{
+		UID:      fmt.Sprintf("task-%d@time-tracker", task.ID),
+		Summary:  task.Description,
+		Start:    task.CreatedAt,
+		End:      end,
+		Modified: modified,
+	}
+}
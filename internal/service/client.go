@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+)
+
+type ClientService struct {
+	repo  repository.Client
+	tx    repository.Transactor
+	audit Audit
+}
+
+func NewClientService(repo repository.Client, tx repository.Transactor, audit Audit) *ClientService {
+	return &ClientService{repo, tx, audit}
+}
+
+// CreateClient returns repoerr.ErrAlreadyExists when the name is taken in any case.
+func (s *ClientService) CreateClient(ctx context.Context, data pgdb.CreateClientInput) (int, error) {
+	var ID int
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ID, err = s.repo.CreateClient(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		client, err := s.repo.GetClient(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityClient, ID, model.AuditActionCreate, nil, client)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ID, nil
+}
+
+func (s *ClientService) GetClient(ctx context.Context, ID int) (model.Client, error) {
+	return s.repo.GetClient(ctx, ID)
+}
+
+func (s *ClientService) ListClients(ctx context.Context) ([]model.Client, error) {
+	return s.repo.ListClients(ctx)
+}
+
+func (s *ClientService) UpdateClient(ctx context.Context, ID int, data pgdb.UpdateClientInput) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetClient(ctx, ID)
+		if err != nil {
+			return err
+		}
+		err = s.repo.UpdateClient(ctx, ID, data)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetClient(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityClient, ID, model.AuditActionUpdate, before, after)
+	})
+}
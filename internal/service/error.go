@@ -4,4 +4,16 @@ import "errors"
 
 var (
 	ErrTaskAlreadyCompleted = errors.New("task already completed")
-)
\ No newline at end of file
+	ErrForbidden            = errors.New("forbidden")
+	ErrUserAnonymized       = errors.New("user already anonymized")
+	ErrImportTooLarge       = errors.New("too many rows to import")
+	ErrProfileRequired      = errors.New("name and surname are required for documents without people info lookup")
+	ErrMergeSameUser        = errors.New("user can not be merged into itself")
+	ErrUserInactive         = errors.New("user is not active")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrInvalidTimezone      = errors.New("invalid timezone")
+	ErrNothingToInvoice     = errors.New("no billable tasks in the period")
+	ErrAlreadyInvoiced      = errors.New("tasks were invoiced concurrently")
+	ErrRateRequired         = errors.New("rate is required, the client has no default rate")
+	ErrTaskInvoiced         = errors.New("task is invoiced")
+)
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/repository/repoerr"
+)
+
+type InvoiceService struct {
+	repo         repository.Invoice
+	projects     repository.Project
+	clients      repository.Client
+	tx           repository.Transactor
+	audit        Audit
+	numberPrefix string
+	currency     string
+	taxRate      float64
+	issuer       string
+}
+
+func NewInvoiceService(repo repository.Invoice, projects repository.Project, clients repository.Client, tx repository.Transactor, audit Audit, numberPrefix, currency string, taxRate float64, issuer string) *InvoiceService {
+	return &InvoiceService{repo, projects, clients, tx, audit, numberPrefix, currency, taxRate, issuer}
+}
+
+// CreateInvoiceInput bills the time in the period, rates are per hour in minor currency units.
+// The client is the one of the project when only ProjectID is given, it provides the defaults of Rate,
+// Currency and Customer.
+type CreateInvoiceInput struct {
+	DateFrom  time.Time
+	DateTo    time.Time
+	ProjectID *int          // any project when nil
+	ClientID  *int          // projects of any client when nil
+	GroupBy   string        // model.InvoiceGroupByTask by default
+	Rate      int64         // the default rate of the client when zero, required for users without UserRates
+	UserRates map[int]int64 // user id -> rate, overrides Rate for the tasks of the user
+	TaxRate   *float64      // percent, the configured one when nil
+	Currency  string        // the client's or the configured one when empty
+	Customer  string        // the client's name and address when empty
+	Notes     string
+}
+
+// CreateInvoice bills the completed billable tasks of the period that no other invoice billed, the tasks are
+// marked with the invoice. Durations are billed to the minute, amounts are rounded half up.
+func (s *InvoiceService) CreateInvoice(ctx context.Context, input CreateInvoiceInput) (model.Invoice, error) {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return model.Invoice{}, ErrForbidden
+	}
+	clientID := input.ClientID
+	if input.ProjectID != nil {
+		project, err := s.projects.GetProject(ctx, *input.ProjectID)
+		if err != nil {
+			return model.Invoice{}, err
+		}
+		if clientID == nil {
+			clientID = project.ClientID
+		}
+	}
+	if clientID != nil {
+		client, err := s.clients.GetClient(ctx, *clientID)
+		if err != nil {
+			return model.Invoice{}, err
+		}
+		input = clientDefaults(input, client)
+	}
+
+	tasks, err := s.repo.ListBillableTasks(ctx, pgdb.BillableTasksFilter{
+		DateFrom:  input.DateFrom,
+		DateTo:    input.DateTo,
+		ProjectID: input.ProjectID,
+		ClientID:  input.ClientID,
+	})
+	if err != nil {
+		return model.Invoice{}, err
+	}
+	if len(tasks) == 0 {
+		return model.Invoice{}, ErrNothingToInvoice
+	}
+
+	invoice := model.Invoice{
+		ProjectID:  input.ProjectID,
+		ClientID:   clientID,
+		Issuer:     s.issuer,
+		Customer:   input.Customer,
+		Currency:   input.Currency,
+		PeriodFrom: input.DateFrom,
+		PeriodTo:   input.DateTo,
+		GroupBy:    input.GroupBy,
+		TaxRate:    s.taxRate,
+		Notes:      input.Notes,
+	}
+	if invoice.Currency == "" {
+		invoice.Currency = s.currency
+	}
+	if invoice.GroupBy == "" {
+		invoice.GroupBy = model.InvoiceGroupByTask
+	}
+	if input.TaxRate != nil {
+		invoice.TaxRate = *input.TaxRate
+	}
+
+	invoice.Lines, err = s.invoiceLines(ctx, tasks, invoice.GroupBy, input)
+	if err != nil {
+		return model.Invoice{}, err
+	}
+	for _, line := range invoice.Lines {
+		invoice.Subtotal += line.Amount
+	}
+	invoice.Tax = invoiceTax(invoice.Subtotal, invoice.TaxRate)
+	invoice.Total = invoice.Subtotal + invoice.Tax
+	for _, task := range tasks {
+		invoice.TaskIDs = append(invoice.TaskIDs, task.ID)
+	}
+
+	var created model.Invoice
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ID, err := s.repo.CreateInvoice(ctx, pgdb.CreateInvoiceInput{NumberPrefix: s.numberPrefix, Invoice: invoice})
+		if err != nil {
+			if errors.Is(err, repoerr.ErrAlreadyExists) {
+				return ErrAlreadyInvoiced
+			}
+			return err
+		}
+
+		created, err = s.repo.GetInvoice(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityInvoice, ID, model.AuditActionCreate, nil, created)
+	})
+	if err != nil {
+		return model.Invoice{}, err
+	}
+	return created, nil
+}
+
+// clientDefaults fills in the rate, currency and customer the input leaves empty from the client.
+func clientDefaults(input CreateInvoiceInput, client model.Client) CreateInvoiceInput {
+	if input.Rate == 0 {
+		input.Rate = client.DefaultRate
+	}
+	if input.Currency == "" {
+		input.Currency = client.Currency
+	}
+	if input.Customer == "" {
+		input.Customer = client.Name
+		if client.Address != "" {
+			input.Customer += ", " + client.Address
+		}
+	}
+	return input
+}
+
+func (s *InvoiceService) GetInvoice(ctx context.Context, ID int) (model.Invoice, error) {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return model.Invoice{}, ErrForbidden
+	}
+	return s.repo.GetInvoice(ctx, ID)
+}
+
+// invoiceLines bills every task on its own line or sums up the tasks of a project billed at the same rate,
+// lines follow the order of the first task they bill.
+func (s *InvoiceService) invoiceLines(ctx context.Context, tasks []model.Task, groupBy string, input CreateInvoiceInput) ([]model.InvoiceLine, error) {
+	type lineKey struct {
+		projectID int
+		rate      int64
+	}
+	var lines []model.InvoiceLine
+	index := make(map[lineKey]int)
+	projects := make(map[int]string)
+
+	for _, task := range tasks {
+		rate, ok := input.UserRates[task.UserID]
+		if !ok {
+			if input.Rate == 0 {
+				return nil, fmt.Errorf("%w: no rate for user %d", ErrRateRequired, task.UserID)
+			}
+			rate = input.Rate
+		}
+
+		if groupBy == model.InvoiceGroupByTask {
+			description := task.Description
+			if description == "" {
+				description = fmt.Sprintf("Задача #%d", task.ID)
+			}
+			lines = append(lines, model.InvoiceLine{Description: description, Duration: task.Duration, Rate: rate})
+			continue
+		}
+
+		key := lineKey{rate: rate}
+		if task.ProjectID != nil {
+			key.projectID = *task.ProjectID
+		}
+		i, ok := index[key]
+		if !ok {
+			name, err := s.projectName(ctx, projects, key.projectID)
+			if err != nil {
+				return nil, err
+			}
+			i = len(lines)
+			index[key] = i
+			lines = append(lines, model.InvoiceLine{Description: name, Rate: rate})
+		}
+		lines[i].Duration += task.Duration
+	}
+
+	for i := range lines {
+		lines[i].Amount = roundDiv(int64(lines[i].Duration)*lines[i].Rate, 60)
+	}
+	return lines, nil
+}
+
+func (s *InvoiceService) projectName(ctx context.Context, names map[int]string, ID int) (string, error) {
+	if ID == 0 {
+		return "Без проекта", nil
+	}
+	if name, ok := names[ID]; ok {
+		return name, nil
+	}
+	project, err := s.projects.GetProject(ctx, ID)
+	if err != nil {
+		return "", err
+	}
+	names[ID] = project.Name
+	return project.Name, nil
+}
+
+// invoiceTax is the tax on the subtotal at the percent rate rounded half up, the rate has two decimals,
+// so in basis points it is exact.
+func invoiceTax(subtotal int64, taxRate float64) int64 {
+	return roundDiv(subtotal*int64(math.Round(taxRate*100)), 10000)
+}
+
+// roundDiv divides non-negative a by b rounding half up.
+func roundDiv(a, b int64) int64 {
+	return (a + b/2) / b
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+)
+
+// stubProjects serves project names to invoiceLines, other methods are not used.
+type stubProjects struct {
+	repository.Project
+	names map[int]string
+}
+
+func (p stubProjects) GetProject(_ context.Context, ID int) (model.Project, error) {
+	return model.Project{ID: ID, Name: p.names[ID]}, nil
+}
+
+func TestRoundDiv(t *testing.T) {
+	tests := []struct {
+		a, b, want int64
+	}{
+		{0, 60, 0},
+		{29, 60, 0},
+		{30, 60, 1},
+		{89, 60, 1},
+		{90, 60, 2},
+		{120, 60, 2},
+		{4999, 10000, 0},
+		{5000, 10000, 1},
+	}
+	for _, tt := range tests {
+		if got := roundDiv(tt.a, tt.b); got != tt.want {
+			t.Errorf("roundDiv(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestInvoiceTax(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtotal int64
+		taxRate  float64
+		want     int64
+	}{
+		{"no tax", 123456, 0, 0},
+		{"whole percent", 100000, 20, 20000},
+		{"fractional percent", 100000, 7.25, 7250},
+		// 0.29 is 28.999... as a float, basis points keep it exact
+		{"inexact float", 100, 0.29, 0},
+		{"inexact float large", 1000000, 0.29, 2900},
+		{"rounds half up", 50, 1, 1},
+		{"rounds down", 49, 1, 0},
+		{"zero subtotal", 0, 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invoiceTax(tt.subtotal, tt.taxRate); got != tt.want {
+				t.Errorf("invoiceTax(%d, %v) = %d, want %d", tt.subtotal, tt.taxRate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvoiceLines(t *testing.T) {
+	project := func(ID int) *int { return &ID }
+	tasks := []model.Task{
+		{ID: 1, UserID: 10, ProjectID: project(1), Description: "Верстка", Duration: 90},
+		{ID: 2, UserID: 20, ProjectID: project(1), Duration: 1},
+		{ID: 3, UserID: 10, ProjectID: project(1), Description: "Ревью", Duration: 45},
+		{ID: 4, UserID: 10, Description: "Созвон", Duration: 30},
+	}
+	s := &InvoiceService{projects: stubProjects{names: map[int]string{1: "Сайт"}}}
+
+	tests := []struct {
+		name    string
+		groupBy string
+		input   CreateInvoiceInput
+		want    []model.InvoiceLine
+		wantErr error
+	}{
+		{
+			name:    "by task",
+			groupBy: model.InvoiceGroupByTask,
+			input:   CreateInvoiceInput{Rate: 1000},
+			want: []model.InvoiceLine{
+				{Description: "Верстка", Duration: 90, Rate: 1000, Amount: 1500},
+				{Description: "Задача #2", Duration: 1, Rate: 1000, Amount: 17},
+				{Description: "Ревью", Duration: 45, Rate: 1000, Amount: 750},
+				{Description: "Созвон", Duration: 30, Rate: 1000, Amount: 500},
+			},
+		},
+		{
+			name:    "user rates override the rate",
+			groupBy: model.InvoiceGroupByTask,
+			input:   CreateInvoiceInput{Rate: 1000, UserRates: map[int]int64{20: 3000}},
+			want: []model.InvoiceLine{
+				{Description: "Верстка", Duration: 90, Rate: 1000, Amount: 1500},
+				{Description: "Задача #2", Duration: 1, Rate: 3000, Amount: 50},
+				{Description: "Ревью", Duration: 45, Rate: 1000, Amount: 750},
+				{Description: "Созвон", Duration: 30, Rate: 1000, Amount: 500},
+			},
+		},
+		{
+			name:    "by project splits rates",
+			groupBy: model.InvoiceGroupByProject,
+			input:   CreateInvoiceInput{Rate: 1000, UserRates: map[int]int64{20: 3000}},
+			want: []model.InvoiceLine{
+				{Description: "Сайт", Duration: 135, Rate: 1000, Amount: 2250},
+				{Description: "Сайт", Duration: 1, Rate: 3000, Amount: 50},
+				{Description: "Без проекта", Duration: 30, Rate: 1000, Amount: 500},
+			},
+		},
+		{
+			name:    "amounts are rounded per line",
+			groupBy: model.InvoiceGroupByProject,
+			input:   CreateInvoiceInput{Rate: 1},
+			want: []model.InvoiceLine{
+				{Description: "Сайт", Duration: 136, Rate: 1, Amount: 2},
+				{Description: "Без проекта", Duration: 30, Rate: 1, Amount: 1},
+			},
+		},
+		{
+			name:    "only user rates",
+			groupBy: model.InvoiceGroupByTask,
+			input:   CreateInvoiceInput{UserRates: map[int]int64{10: 600, 20: 600}},
+			want: []model.InvoiceLine{
+				{Description: "Верстка", Duration: 90, Rate: 600, Amount: 900},
+				{Description: "Задача #2", Duration: 1, Rate: 600, Amount: 10},
+				{Description: "Ревью", Duration: 45, Rate: 600, Amount: 450},
+				{Description: "Созвон", Duration: 30, Rate: 600, Amount: 300},
+			},
+		},
+		{
+			name:    "no rate for a user",
+			groupBy: model.InvoiceGroupByTask,
+			input:   CreateInvoiceInput{UserRates: map[int]int64{10: 600}},
+			wantErr: ErrRateRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.invoiceLines(context.Background(), tasks, tt.groupBy, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("invoiceLines error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invoiceLines = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/pkg/publish"
+)
+
+type OutboxService struct {
+	repo       repository.Outbox
+	publisher  Publisher
+	batchSize  int
+	lease      time.Duration // of claimed events, longer than publishing a batch takes
+	retryDelay time.Duration
+	retention  time.Duration // of published events
+}
+
+func NewOutboxService(repo repository.Outbox, publisher Publisher, batchSize int, lease, retryDelay, retention time.Duration) *OutboxService {
+	return &OutboxService{repo, publisher, batchSize, lease, retryDelay, retention}
+}
+
+// Relay publishes the outbox events due by now, at least once and in order within an aggregate: an event is
+// marked published only after the publisher accepted it, and once an event failed the later events of its
+// aggregate wait until it is published on a retry. Published events are purged after the retention period.
+func (s *OutboxService) Relay(ctx context.Context) error {
+	events, err := s.repo.ClaimOutboxEvents(ctx, s.batchSize, s.lease)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	failed := make(map[string]bool)
+	for _, event := range events {
+		aggregate := fmt.Sprintf("%s:%d", event.Aggregate, event.AggregateID)
+		now := time.Now()
+		data := pgdb.UpdateOutboxEventInput{Attempts: event.Attempts, NextAttemptAt: &now, LastError: event.LastError}
+		if !failed[aggregate] {
+			err = s.publisher.Publish(ctx, outboxMessage(event))
+			data.Attempts++
+			if err == nil {
+				data.NextAttemptAt = nil
+				data.PublishedAt = &now
+				data.LastError = ""
+			} else {
+				failed[aggregate] = true
+				next := now.Add(s.retryDelay)
+				data.NextAttemptAt = &next
+				data.LastError = err.Error()
+				errs = append(errs, fmt.Errorf("outbox event %d: %w", event.ID, err))
+			}
+		}
+		// the events queued behind a failed one are released as due, the failed one holds them back
+		if err := s.repo.UpdateOutboxEvent(ctx, event.ID, data); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.repo.PurgeOutboxEvents(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func outboxMessage(event model.OutboxEvent) publish.Message {
+	return publish.Message{
+		ID:          event.ID,
+		Aggregate:   event.Aggregate,
+		AggregateID: event.AggregateID,
+		Event:       event.Event,
+		Payload:     event.Payload,
+		CreatedAt:   event.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+)
+
+type ProjectService struct {
+	repo    repository.Project
+	clients repository.Client
+	tx      repository.Transactor
+	audit   Audit
+}
+
+func NewProjectService(repo repository.Project, clients repository.Client, tx repository.Transactor, audit Audit) *ProjectService {
+	return &ProjectService{repo, clients, tx, audit}
+}
+
+// CreateProject returns repoerr.ErrAlreadyExists when the name is taken in any case and repoerr.ErrNotFound
+// when the client does not exist.
+func (s *ProjectService) CreateProject(ctx context.Context, name string, clientID *int) (int, error) {
+	if clientID != nil {
+		_, err := s.clients.GetClient(ctx, *clientID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	var ID int
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ID, err = s.repo.CreateProject(ctx, pgdb.CreateProjectInput{Name: name, ClientID: clientID})
+		if err != nil {
+			return err
+		}
+
+		project, err := s.repo.GetProject(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityProject, ID, model.AuditActionCreate, nil, project)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ID, nil
+}
+
+func (s *ProjectService) ListProjects(ctx context.Context) ([]model.Project, error) {
+	return s.repo.ListProjects(ctx)
+}
+
+// SetProjectClient moves the project to the client, nil clientID leaves it without one.
+func (s *ProjectService) SetProjectClient(ctx context.Context, ID int, clientID *int) error {
+	if clientID != nil {
+		_, err := s.clients.GetClient(ctx, *clientID)
+		if err != nil {
+			return err
+		}
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetProject(ctx, ID)
+		if err != nil {
+			return err
+		}
+		err = s.repo.SetProjectClient(ctx, ID, clientID)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetProject(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityProject, ID, model.AuditActionUpdate, before, after)
+	})
+}
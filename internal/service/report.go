@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+)
+
+type ReportService struct {
+	repo repository.Report
+}
+
+func NewReportService(repo repository.Report) *ReportService {
+	return &ReportService{repo}
+}
+
+func (s *ReportService) Worklog(ctx context.Context, filter pgdb.WorklogFilter, fn func(model.WorklogEntry) error) error {
+	return s.repo.ForEachWorklogEntry(ctx, filter, fn)
+}
+
+func (s *ReportService) ClientWorklog(ctx context.Context, filter pgdb.WorklogFilter, fn func(model.ClientWorklogEntry) error) error {
+	return s.repo.ForEachClientWorklogEntry(ctx, filter, fn)
+}
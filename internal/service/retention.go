@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+)
+
+type RetentionService struct {
+	users  repository.User
+	tasks  repository.Task
+	tx     repository.Transactor
+	audit  Audit
+	period time.Duration
+}
+
+func NewRetentionService(reps *repository.Repositories, audit Audit, period time.Duration) *RetentionService {
+	return &RetentionService{
+		users:  reps.User,
+		tasks:  reps.Task,
+		tx:     reps.Transactor,
+		audit:  audit,
+		period: period,
+	}
+}
+
+// Purge permanently removes tasks and users that stayed soft deleted longer than the retention period.
+func (s *RetentionService) Purge(ctx context.Context) error {
+	deletedBefore := time.Now().Add(-s.period)
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		taskIDs, err := s.tasks.PurgeTasks(ctx, deletedBefore)
+		if err != nil {
+			return err
+		}
+		for _, ID := range taskIDs {
+			if err := s.audit.Record(ctx, model.AuditEntityTask, ID, model.AuditActionPurge, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		userIDs, err := s.users.PurgeUsers(ctx, deletedBefore)
+		if err != nil {
+			return err
+		}
+		for _, ID := range userIDs {
+			if err := s.audit.Record(ctx, model.AuditEntityUser, ID, model.AuditActionPurge, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
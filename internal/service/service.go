@@ -2,41 +2,209 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 	"time-tracker/config"
 	"time-tracker/internal/model"
 	"time-tracker/internal/repository"
 	"time-tracker/internal/repository/pgdb"
+	"time-tracker/pkg/document"
+	"time-tracker/pkg/notify"
+	"time-tracker/pkg/peopleinfo"
+	"time-tracker/pkg/publish"
 )
 
 type User interface {
-	CreateUser(ctx context.Context, passportNumber string) (int, error)
-	GetUser(ctx context.Context, ID int) (model.User, error)
-	ListUsers(ctx context.Context, filter ListUsersFilter) ([]model.User, error)
+	CreateUser(ctx context.Context, input CreateUserInput) (model.User, error)
+	GetUser(ctx context.Context, ID int, includeDeleted bool) (model.User, error)
+	ListUsers(ctx context.Context, filter pgdb.ListUsersFilter) (pgdb.Page[model.User], error)
+	ExportUsers(ctx context.Context, filter pgdb.ListUsersFilter, fn func(model.User) error) error
 	UpdateUser(ctx context.Context, ID int, data pgdb.UpdateUserInput) error
 	DeleteUser(ctx context.Context, id int) error
+	RestoreUser(ctx context.Context, id int) error
+	ExportUser(ctx context.Context, id int) (model.UserExport, error)
+	AnonymizeUser(ctx context.Context, id int) error
+	EnrichPendingUsers(ctx context.Context) error
+	ImportUsers(ctx context.Context, rows []ImportUserRow, dryRun bool) ([]ImportUserResult, error)
+	ImportMaxRows() int
+	FindDuplicates(ctx context.Context, id int, minScore float64) ([]model.DuplicateCandidate, error)
+	MergeUsers(ctx context.Context, sourceID, targetID int) error
+	ChangeUserStatus(ctx context.Context, id int, input ChangeUserStatusInput) error
+	ListUserStatusChanges(ctx context.Context, id int) ([]model.UserStatusChange, error)
 }
 
 type Task interface {
 	CreateTask(ctx context.Context, data pgdb.CreateTaskInput) (int, error)
-	GetTask(ctx context.Context, ID int) (model.Task, error)
-	ListTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter) ([]model.Task, error)
+	GetTask(ctx context.Context, ID int, includeDeleted bool) (model.Task, error)
+	ListTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter) (pgdb.Page[model.Task], error)
+	ExportTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter, fn func(model.Task) error) error
 	CompleteTask(ctx context.Context, ID int) error
+	DeleteTask(ctx context.Context, ID int) error
+	RestoreTask(ctx context.Context, ID int) error
+}
+
+type Project interface {
+	CreateProject(ctx context.Context, name string, clientID *int) (int, error)
+	ListProjects(ctx context.Context) ([]model.Project, error)
+	SetProjectClient(ctx context.Context, ID int, clientID *int) error
+}
+
+type Client interface {
+	CreateClient(ctx context.Context, data pgdb.CreateClientInput) (int, error)
+	GetClient(ctx context.Context, ID int) (model.Client, error)
+	ListClients(ctx context.Context) ([]model.Client, error)
+	UpdateClient(ctx context.Context, ID int, data pgdb.UpdateClientInput) error
+}
+
+type Budget interface {
+	SetBudget(ctx context.Context, projectID int, input SetBudgetInput) error
+	GetBudget(ctx context.Context, projectID int) (model.ProjectBudget, error)
+	DeleteBudget(ctx context.Context, projectID int) error
+	CheckBudget(ctx context.Context, projectID int, at time.Time) error
+	NotifyPendingAlerts(ctx context.Context) error
+	Burndown(ctx context.Context, projectID int, at time.Time) (model.BudgetBurndown, error)
+}
+
+type Invoice interface {
+	CreateInvoice(ctx context.Context, input CreateInvoiceInput) (model.Invoice, error)
+	GetInvoice(ctx context.Context, ID int) (model.Invoice, error)
+}
+
+type Webhook interface {
+	CreateSubscription(ctx context.Context, input CreateWebhookInput) (model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, ID int) error
+	ListDeliveries(ctx context.Context, subscriptionID int) ([]model.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID int) (model.WebhookDelivery, error)
+	DeliverPending(ctx context.Context) error
+}
+
+// Publisher hands the outbox events over, see publish.Multi for combining several of them.
+type Publisher interface {
+	Publish(ctx context.Context, m publish.Message) error
+}
+
+// TrackerImport reads the export from r, see TrackerImportService.ImportTimeEntries.
+type TrackerImport interface {
+	ImportTimeEntries(ctx context.Context, r io.Reader, input TrackerImportInput) (TrackerImportReport, error)
+}
+
+type Audit interface {
+	Record(ctx context.Context, entity string, entityID int, action string, before, after any) error
+	ListRecords(ctx context.Context, filter pgdb.ListAuditRecordsFilter) ([]model.AuditRecord, error)
+	ListEntityRecords(ctx context.Context, entity string, entityIDs []int) ([]model.AuditRecord, error)
+	Scrub(ctx context.Context, entity string, entityID int) error
+}
+
+// Report streams aggregates row by row, so that large periods can be exported without loading them in memory.
+type Report interface {
+	Worklog(ctx context.Context, filter pgdb.WorklogFilter, fn func(model.WorklogEntry) error) error
+	ClientWorklog(ctx context.Context, filter pgdb.WorklogFilter, fn func(model.ClientWorklogEntry) error) error
+}
+
+type Calendar interface {
+	IssueCalendarToken(ctx context.Context, userID int) (string, error)
+	CalendarFeed(ctx context.Context, userID int, token string, window time.Duration) (CalendarFeed, error)
+}
+
+// PeopleInfo is an enrichment provider, see peopleinfo.Chain for combining several of them.
+type PeopleInfo interface {
+	GetInfo(ctx context.Context, passportSerie, passportNumber string) (peopleinfo.Info, error)
+}
+
+// Notifier delivers the notifications of the services, see notify.Multi for combining several of them.
+type Notifier interface {
+	Notify(ctx context.Context, n notify.Notification) error
+}
+
+type ProfileSync interface {
+	SyncProfiles(ctx context.Context) error
+}
+
+type Retention interface {
+	Purge(ctx context.Context) error
+}
+
+type Outbox interface {
+	Relay(ctx context.Context) error
 }
 
 type Services struct {
 	User
 	Task
+	Project
+	Client
+	Budget
+	Invoice
+	Webhook
+	TrackerImport
+	Audit
+	Report
+	Calendar
+	Retention
+	ProfileSync
+	Outbox
 }
 
 type ServiceDeps struct {
-	Reps    *repository.Repositories
-	ApiURLS config.API
+	Reps       *repository.Repositories
+	PeopleInfo PeopleInfo
+	Retention  config.Retention
+	Sync       config.Sync
+	Import     config.Import
+	Calendar   config.Calendar
+	Invoice    config.Invoice
+	Budget     config.Budget
+	Notifier   Notifier
+	Webhook    config.Webhook
+	// WebhookSender posts the webhook deliveries
+	WebhookSender WebhookSender
+	Outbox        config.Outbox
+	// Publisher relays the outbox events, built from Outbox.Publishers
+	Publisher Publisher
+	// BudgetLocation is the time zone of budget periods, loaded from Budget.Timezone
+	BudgetLocation *time.Location
+	// CalendarLocation is the time zone of calendar feeds, loaded from Calendar.Timezone
+	CalendarLocation *time.Location
 }
 
 func NewServices(deps ServiceDeps) *Services {
-	userService := NewUserService(deps.Reps, deps.ApiURLS.UserApiURl)
+	auditService := NewAuditService(deps.Reps)
+	webhookService := NewWebhookService(deps.Reps, deps.Reps, auditService, deps.WebhookSender, deps.Webhook.MaxAttempts,
+		deps.Webhook.Backoff, deps.Webhook.BackoffMax, 2*deps.Webhook.Timeout, deps.Webhook.BatchSize,
+		deps.Webhook.Retention,
+	)
+	userService := NewUserService(deps.Reps, deps.Reps, deps.Reps, auditService, deps.PeopleInfo, document.DefaultRegistry(),
+		deps.Import.Workers, deps.Import.MaxRows,
+	)
+	budgetService := NewBudgetService(deps.Reps, deps.Reps, deps.Reps, deps.Reps, auditService, deps.Notifier,
+		deps.Budget.Thresholds, deps.BudgetLocation, deps.Budget.BatchSize,
+	)
 	return &Services{
-		User: userService,
-		Task: NewTaskService(deps.Reps, userService),
+		User:    userService,
+		Task:    NewTaskService(deps.Reps, deps.Reps, userService, budgetService, deps.Reps, auditService),
+		Project: NewProjectService(deps.Reps, deps.Reps, deps.Reps, auditService),
+		Client:  NewClientService(deps.Reps, deps.Reps, auditService),
+		Budget:  budgetService,
+		Webhook: webhookService,
+		Invoice: NewInvoiceService(deps.Reps, deps.Reps, deps.Reps, deps.Reps, auditService,
+			deps.Invoice.NumberPrefix, deps.Invoice.Currency, deps.Invoice.TaxRate, deps.Invoice.Issuer,
+		),
+		TrackerImport: NewTrackerImportService(deps.Reps, deps.Reps, deps.Reps, budgetService, deps.Reps, auditService,
+			deps.Import.TaskMaxRows, deps.Import.TaskTimezone,
+		),
+		Audit:  auditService,
+		Report: NewReportService(deps.Reps),
+		Calendar: NewCalendarService(deps.Reps, deps.Reps, deps.Reps, auditService,
+			deps.Calendar.Window, deps.Calendar.MaxWindow, deps.CalendarLocation,
+		),
+
+		Retention: NewRetentionService(deps.Reps, auditService, deps.Retention.Period),
+		ProfileSync: NewProfileSyncService(deps.Reps, deps.Reps, auditService, deps.PeopleInfo,
+			deps.Sync.Interval, deps.Sync.BatchSize, deps.Sync.RateLimit,
+		),
+		Outbox: NewOutboxService(deps.Reps, deps.Publisher, deps.Outbox.BatchSize,
+			deps.Outbox.Lease, deps.Outbox.RetryDelay, deps.Outbox.Retention,
+		),
 	}
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/pkg/peopleinfo"
+)
+
+const defaultSyncRateLimit = 5
+
+// ProfileSyncService keeps user profiles in line with the People info API, people change surnames and addresses.
+type ProfileSyncService struct {
+	repo       repository.User
+	tx         repository.Transactor
+	audit      Audit
+	peopleInfo PeopleInfo
+
+	interval  time.Duration
+	batchSize int
+	rateLimit float64 // requests per second
+}
+
+func NewProfileSyncService(repo repository.User, tx repository.Transactor, audit Audit, peopleInfo PeopleInfo, interval time.Duration, batchSize int, rateLimit float64) *ProfileSyncService {
+	if rateLimit <= 0 {
+		rateLimit = defaultSyncRateLimit
+	}
+	return &ProfileSyncService{
+		repo:       repo,
+		tx:         tx,
+		audit:      audit,
+		peopleInfo: peopleInfo,
+		interval:   interval,
+		batchSize:  batchSize,
+		rateLimit:  rateLimit,
+	}
+}
+
+// SyncProfiles re-queries the People info API for every user not synchronised during the last interval.
+// Pinned users are skipped, synced_at makes an interrupted run resume where it stopped.
+func (s *ProfileSyncService) SyncProfiles(ctx context.Context) error {
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / s.rateLimit))
+	defer limiter.Stop()
+
+	syncedBefore := time.Now().Add(-s.interval)
+	lastID := 0
+	for {
+		users, err := s.repo.ListUsersForSync(ctx, syncedBefore, lastID, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		for _, user := range users {
+			lastID = user.ID
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-limiter.C:
+			}
+
+			if err := s.syncProfile(ctx, user); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *ProfileSyncService) syncProfile(ctx context.Context, user model.User) error {
+	passportNums := strings.Split(user.PassportNumber, " ")
+	if len(passportNums) != 2 {
+		return s.repo.MarkUserSynced(ctx, user.ID, time.Now())
+	}
+
+	info, err := s.peopleInfo.GetInfo(ctx, passportNums[0], passportNums[1])
+	if errors.Is(err, peopleinfo.ErrBadResponse) || errors.Is(err, peopleinfo.ErrNotFound) {
+		// the providers don't know the person anymore, keep what we have
+		return s.repo.MarkUserSynced(ctx, user.ID, time.Now())
+	}
+	if err != nil {
+		return fmt.Errorf("ProfileSyncService.syncProfile - s.peopleInfo.GetInfo: %w", err)
+	}
+
+	data, changed := profileChanges(user, info)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if changed {
+			if err := s.repo.UpdateUser(ctx, user.ID, data); err != nil {
+				return err
+			}
+			after, err := s.repo.GetUser(ctx, user.ID, false)
+			if err != nil {
+				return err
+			}
+			if err := s.audit.Record(ctx, model.AuditEntityUser, user.ID, model.AuditActionResync, user, after); err != nil {
+				return err
+			}
+		}
+		return s.repo.MarkUserSynced(ctx, user.ID, time.Now())
+	})
+}
+
+func profileChanges(user model.User, info peopleinfo.Info) (pgdb.UpdateUserInput, bool) {
+	var data pgdb.UpdateUserInput
+	changed := false
+	if info.Name != user.Name {
+		data.Name, changed = &info.Name, true
+	}
+	if info.Surname != user.Surname {
+		data.Surname, changed = &info.Surname, true
+	}
+	if info.Patronymic != user.Patronymic {
+		data.Patronymic, changed = &info.Patronymic, true
+	}
+	if info.Address != user.Address {
+		data.Address, changed = &info.Address, true
+	}
+	return data, changed
+}
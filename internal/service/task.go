@@ -10,42 +10,150 @@ import (
 
 type TaskService struct {
 	repo repository.Task
+	projects repository.Project
 	userService User
+	budgets Budget
+	tx repository.Transactor
+	audit Audit
 }
 
-func NewTaskService(repo repository.Task, userService User) *TaskService {
-	return &TaskService{repo, userService}
+func NewTaskService(repo repository.Task, projects repository.Project, userService User, budgets Budget, tx repository.Transactor, audit Audit) *TaskService {
+	return &TaskService{repo, projects, userService, budgets, tx, audit}
 }
 
 func (s *TaskService) CreateTask(ctx context.Context, data pgdb.CreateTaskInput) (int, error) {
-	return s.repo.CreateTask(ctx, data)
+	user, err := s.userService.GetUser(ctx, data.UserID, false)
+	if err != nil {
+		return 0, err
+	}
+	if user.Status != model.UserStatusActive {
+		return 0, ErrUserInactive
+	}
+	if data.ProjectID != nil {
+		_, err := s.projects.GetProject(ctx, *data.ProjectID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var ID int
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ID, err = s.repo.CreateTask(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		task, err := s.repo.GetTask(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityTask, ID, model.AuditActionCreate, nil, task)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ID, nil
 }
 
-func (s *TaskService) GetTask(ctx context.Context, ID int) (model.Task, error) {
-	return s.repo.GetTask(ctx, ID)
+func (s *TaskService) GetTask(ctx context.Context, ID int, includeDeleted bool) (model.Task, error) {
+	if includeDeleted && !HasPermission(ctx, PermissionAdmin) {
+		return model.Task{}, ErrForbidden
+	}
+	return s.repo.GetTask(ctx, ID, includeDeleted)
 }
 
-func (s *TaskService) ListTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter) ([]model.Task, error) {
-	_, err := s.userService.GetUser(ctx, userID)
+func (s *TaskService) ListTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter) (pgdb.Page[model.Task], error) {
+	_, err := s.userService.GetUser(ctx, userID, filter.IncludeDeleted)
 	if err != nil {
-		return nil, err
+		return pgdb.Page[model.Task]{}, err
 	}
 	return s.repo.ListTasks(ctx, userID, filter)
 }
 
-func (s *TaskService) CompleteTask(ctx context.Context, ID int) error {
-	task, err := s.repo.GetTask(ctx, ID)
+// ExportTasks streams the tasks to fn, see ListTasks.
+func (s *TaskService) ExportTasks(ctx context.Context, userID int, filter pgdb.ListTasksFilter, fn func(model.Task) error) error {
+	_, err := s.userService.GetUser(ctx, userID, filter.IncludeDeleted)
 	if err != nil {
 		return err
 	}
+	return s.repo.ForEachTask(ctx, userID, filter, fn)
+}
+
+func (s *TaskService) CompleteTask(ctx context.Context, ID int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := s.repo.GetTask(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+
+		if task.Completed {
+			return ErrTaskAlreadyCompleted
+		}
+
+		err = s.repo.UpdateTask(ctx, ID, pgdb.UpdateTaskInput{
+			Completed: true,
+			UpdatedAt: time.Now(),
+			Duration:  int(time.Since(task.CreatedAt).Minutes()),
+		})
+		if err != nil {
+			return err
+		}
+
+		completed, err := s.repo.GetTask(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+		err = s.audit.Record(ctx, model.AuditEntityTask, ID, model.AuditActionComplete, task, completed)
+		if err != nil {
+			return err
+		}
+		if completed.ProjectID == nil {
+			return nil
+		}
+		return s.budgets.CheckBudget(ctx, *completed.ProjectID, completed.CreatedAt)
+	})
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, ID int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetTask(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+		if before.InvoiceID != nil {
+			return ErrTaskInvoiced
+		}
+		err = s.repo.DeleteTask(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetTask(ctx, ID, true)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityTask, ID, model.AuditActionDelete, before, after)
+	})
+}
 
-	if task.Completed {
-		return ErrTaskAlreadyCompleted
+func (s *TaskService) RestoreTask(ctx context.Context, ID int) error {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return ErrForbidden
 	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetTask(ctx, ID, true)
+		if err != nil {
+			return err
+		}
+		err = s.repo.RestoreTask(ctx, ID)
+		if err != nil {
+			return err
+		}
 
-	return s.repo.UpdateTask(ctx, ID, pgdb.UpdateTaskInput{
-		Completed: true,
-		UpdatedAt: time.Now(),
-		Duration:  int(time.Since(task.CreatedAt).Minutes()),
+		after, err := s.repo.GetTask(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityTask, ID, model.AuditActionRestore, before, after)
 	})
 }
\ No newline at end of file
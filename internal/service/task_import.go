@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/tracker"
+)
+
+const (
+	ImportStatusUnknownUser   = "unknown_user"
+	ImportStatusAmbiguousUser = "ambiguous_user"
+
+	maxDescriptionLength = 128 // md.tasks.description
+)
+
+type TrackerImportInput struct {
+	Format   string // one of tracker.Formats, detected from the header when empty
+	Timezone string // of the local times in the export, the configured one when empty
+	DryRun   bool
+}
+
+type TrackerImportResult struct {
+	Line       int
+	Status     string
+	ExternalID string
+	UserID     int
+	ProjectID  int // zero without a project and for projects the dry run would create
+	TaskID     int // the created task or the one imported before
+	Error      string
+}
+
+type TrackerImportReport struct {
+	Format   string
+	Projects []string // projects created by the import, in the dry run the ones it would create
+	Results  []TrackerImportResult
+	Warnings []string // budget checks that failed after the tasks were imported
+}
+
+// TrackerImportService imports completed tasks from the exports of other trackers, see package tracker.
+type TrackerImportService struct {
+	tasks    repository.Task
+	users    repository.User
+	projects repository.Project
+	budgets  Budget
+	tx       repository.Transactor
+	audit    Audit
+	maxRows  int
+	timezone string
+}
+
+func NewTrackerImportService(tasks repository.Task, users repository.User, projects repository.Project, budgets Budget, tx repository.Transactor, audit Audit, maxRows int, timezone string) *TrackerImportService {
+	return &TrackerImportService{tasks, users, projects, budgets, tx, audit, maxRows, timezone}
+}
+
+// trackerImport holds the lookups shared by the rows of one import.
+type trackerImport struct {
+	*TrackerImportService
+	dryRun   bool
+	users    map[string][]model.User // user key -> at most two matches
+	projects map[string]int          // lower case name -> id, zero for projects the dry run would create
+	result   TrackerImportReport
+	// the created tasks of a project, their budget periods are checked once the entries are imported
+	budgetChecks map[budgetCheck]bool
+}
+
+// budgetCheck is a project and a start time of its tasks truncated to half an hour, fine enough for the
+// period boundaries of any budget time zone.
+type budgetCheck struct {
+	projectID int
+	at        time.Time
+}
+
+// ImportTimeEntries matches the users of the entries by email, then by full name, finds or creates their projects
+// and creates a completed task per entry. Entries are identified by the tracker entry id or, as the exports usually
+// have none, by a hash of their fields, so re-importing a file skips the entries imported before. Users of any
+// lifecycle status are matched, the entries are history. In the dry run nothing is written.
+func (s *TrackerImportService) ImportTimeEntries(ctx context.Context, r io.Reader, input TrackerImportInput) (TrackerImportReport, error) {
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.timezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return TrackerImportReport{}, fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
+	}
+
+	format, entries, err := tracker.Read(r, input.Format, loc, s.maxRows)
+	if errors.Is(err, tracker.ErrTooManyEntries) {
+		return TrackerImportReport{}, ErrImportTooLarge
+	}
+	if err != nil {
+		return TrackerImportReport{}, err
+	}
+
+	imp := &trackerImport{
+		TrackerImportService: s,
+		dryRun:               input.DryRun,
+		users:                make(map[string][]model.User),
+		projects:             make(map[string]int),
+		budgetChecks:         make(map[budgetCheck]bool),
+		result:               TrackerImportReport{Format: format, Results: make([]TrackerImportResult, len(entries))},
+	}
+
+	seen := make(map[string]int, len(entries))
+	var externalIDs []string
+	for i, entry := range entries {
+		result := &imp.result.Results[i]
+		result.Line = entry.Line
+		if entry.Err != nil {
+			result.Status = ImportStatusInvalid
+			result.Error = entry.Err.Error()
+			continue
+		}
+		result.ExternalID = externalID(format, entry)
+		if line, ok := seen[result.ExternalID]; ok {
+			result.Status = ImportStatusDuplicate
+			result.Error = fmt.Sprintf("entry repeats line %d", line)
+			continue
+		}
+		seen[result.ExternalID] = entry.Line
+		externalIDs = append(externalIDs, result.ExternalID)
+	}
+
+	imported, err := s.tasks.GetTaskIDsByExternalID(ctx, externalIDs)
+	if err != nil {
+		return TrackerImportReport{}, err
+	}
+	for i, entry := range entries {
+		result := &imp.result.Results[i]
+		if result.Status != "" {
+			continue
+		}
+		if ID, ok := imported[result.ExternalID]; ok {
+			result.Status = ImportStatusExists
+			result.TaskID = ID
+			continue
+		}
+		imp.importEntry(ctx, entry, result)
+	}
+
+	// the tasks are committed by now, so a failed check doesn't fail the import, the next one raises its alerts
+	for check := range imp.budgetChecks {
+		err := s.budgets.CheckBudget(ctx, check.projectID, check.at)
+		if err != nil {
+			imp.result.Warnings = append(imp.result.Warnings,
+				fmt.Sprintf("budget check of project %d: %v", check.projectID, err))
+		}
+	}
+	return imp.result, nil
+}
+
+func (imp *trackerImport) importEntry(ctx context.Context, entry tracker.Entry, result *TrackerImportResult) {
+	fail := func(err error) {
+		result.Status = ImportStatusFailed
+		result.Error = err.Error()
+	}
+
+	users, err := imp.findUsers(ctx, entry)
+	if err != nil {
+		fail(err)
+		return
+	}
+	switch len(users) {
+	case 0:
+		result.Status = ImportStatusUnknownUser
+		return
+	case 1:
+		result.UserID = users[0].ID
+	default:
+		result.Status = ImportStatusAmbiguousUser
+		result.Error = fmt.Sprintf("matches users %d and %d", users[0].ID, users[1].ID)
+		return
+	}
+
+	var projectID *int
+	if entry.Project != "" {
+		result.ProjectID, err = imp.findProject(ctx, entry.Project)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if result.ProjectID != 0 {
+			projectID = &result.ProjectID
+		}
+	}
+
+	if imp.dryRun {
+		result.Status = ImportStatusValid
+		return
+	}
+	var ID int
+	err = imp.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ID, err = imp.tasks.ImportTask(ctx, pgdb.ImportTaskInput{
+			UserID:      result.UserID,
+			ProjectID:   projectID,
+			Description: truncate(entry.Description, maxDescriptionLength),
+			Billable:    entry.Billable,
+			Start:       entry.Start,
+			End:         entry.End,
+			ExternalID:  result.ExternalID,
+		})
+		if err != nil {
+			return err
+		}
+
+		task, err := imp.tasks.GetTask(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+		return imp.audit.Record(ctx, model.AuditEntityTask, ID, model.AuditActionImport, nil, task)
+	})
+	if errors.Is(err, repoerr.ErrAlreadyExists) {
+		// imported concurrently since the lookup
+		result.Status = ImportStatusExists
+		return
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+	result.TaskID = ID
+	result.Status = ImportStatusCreated
+	if projectID != nil {
+		imp.budgetChecks[budgetCheck{*projectID, entry.Start.Truncate(30 * time.Minute)}] = true
+	}
+}
+
+func (imp *trackerImport) findUsers(ctx context.Context, entry tracker.Entry) ([]model.User, error) {
+	key := strings.ToLower(entry.Email) + "|" + strings.ToLower(entry.User)
+	if users, ok := imp.users[key]; ok {
+		return users, nil
+	}
+	users, err := imp.TrackerImportService.users.FindUsersByEmailOrName(ctx, entry.Email, entry.User, 2)
+	if err != nil {
+		return nil, err
+	}
+	imp.users[key] = users
+	return users, nil
+}
+
+// findProject returns the id of the project with the name in any case, a missing project is created,
+// in the dry run it is only reported and zero is returned.
+func (imp *trackerImport) findProject(ctx context.Context, name string) (int, error) {
+	key := strings.ToLower(name)
+	if ID, ok := imp.projects[key]; ok {
+		return ID, nil
+	}
+
+	project, err := imp.TrackerImportService.projects.GetProjectByName(ctx, name)
+	switch {
+	case err == nil:
+		imp.projects[key] = project.ID
+		return project.ID, nil
+	case !errors.Is(err, repoerr.ErrNotFound):
+		return 0, err
+	}
+
+	if imp.dryRun {
+		imp.projects[key] = 0
+		imp.result.Projects = append(imp.result.Projects, name)
+		return 0, nil
+	}
+	var ID int
+	err = imp.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ID, err = imp.TrackerImportService.projects.CreateProject(ctx, pgdb.CreateProjectInput{Name: name})
+		if err != nil {
+			return err
+		}
+
+		project, err := imp.TrackerImportService.projects.GetProject(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return imp.audit.Record(ctx, model.AuditEntityProject, ID, model.AuditActionCreate, nil, project)
+	})
+	if errors.Is(err, repoerr.ErrAlreadyExists) {
+		// created concurrently since the lookup
+		project, err = imp.TrackerImportService.projects.GetProjectByName(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+		imp.projects[key] = project.ID
+		return project.ID, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	imp.projects[key] = ID
+	imp.result.Projects = append(imp.result.Projects, name)
+	return ID, nil
+}
+
+// externalID prefixes the tracker entry id with the format. Without an id the entry is identified by its fields
+// with the times as exported, so that the time zone the file is imported in does not change the id.
+func externalID(format string, entry tracker.Entry) string {
+	if entry.ID != "" {
+		return truncate(format+":"+entry.ID, 128)
+	}
+	const layout = "2006-01-02T15:04:05"
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		strings.ToLower(entry.Email), entry.User, entry.Project, entry.Description,
+		entry.Start.Format(layout), entry.End.Format(layout),
+	}, "\x00")))
+	return format + ":" + hex.EncodeToString(sum[:16])
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length])
+}
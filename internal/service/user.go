@@ -2,97 +2,432 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"strings"
-	"time"
 	"time-tracker/internal/model"
 	"time-tracker/internal/repository"
 	"time-tracker/internal/repository/pgdb"
 	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/document"
+	"time-tracker/pkg/peopleinfo"
+)
+
+const (
+	enrichmentBatchSize = 50
+	exportPageLimit     = 100
 )
 
 type UserService struct {
 	repo       repository.User
-	userApiURl string
+	tasks      repository.Task
+	tx         repository.Transactor
+	audit      Audit
+	peopleInfo PeopleInfo
+	documents  *document.Registry
+
+	importWorkers int
+	importMaxRows int
 }
 
-func NewUserService(repo repository.User, userApiURl string) *UserService {
+func NewUserService(repo repository.User, tasks repository.Task, tx repository.Transactor, audit Audit, peopleInfo PeopleInfo, documents *document.Registry, importWorkers, importMaxRows int) *UserService {
+	if importWorkers <= 0 {
+		importWorkers = defaultImportWorkers
+	}
 	return &UserService{
 		repo:       repo,
-		userApiURl: userApiURl,
+		tasks:      tasks,
+		tx:         tx,
+		audit:      audit,
+		peopleInfo: peopleInfo,
+		documents:  documents,
+
+		importWorkers: importWorkers,
+		importMaxRows: importMaxRows,
 	}
 }
 
-func (s *UserService) CreateUser(ctx context.Context, passportNumber string) (int, error) {
-	_, err := s.repo.GeUsertByPassportNumber(ctx, passportNumber)
-	if err == nil {
-		return 0, repoerr.ErrAlreadyExists
+// CreateUserInput identifies the user by an identity document (a russian passport by default).
+// A profile with a name and surname is taken as is, otherwise it is looked up, which only
+// document types supported by the people info providers allow.
+type CreateUserInput struct {
+	DocumentType    string
+	DocumentCountry string
+	PassportNumber  string // number of the document
+	Name            string
+	Surname         string
+	Patronymic      string
+	Address         string
+	Email           string // optional, the providers do not know it
+}
+
+// CreateUser enriches the user from the configured providers, when they are unavailable the user
+// is still created in the pending_enrichment status and EnrichPendingUsers completes it later.
+func (s *UserService) CreateUser(ctx context.Context, input CreateUserInput) (model.User, error) {
+	data, err := s.prepareUser(ctx, input)
+	if err != nil {
+		return model.User{}, err
 	}
+	return s.createUser(ctx, data)
+}
 
-	passportNums := strings.Split(passportNumber, " ")
-	queryParams := fmt.Sprintf("?passportSerie=%s&passportNumber=%s", passportNums[0], passportNums[1])
+// prepareUser validates the document and fills the profile without writing anything.
+func (s *UserService) prepareUser(ctx context.Context, input CreateUserInput) (pgdb.CreateUserInput, error) {
+	doc, err := s.documents.Validate(document.Document{
+		Type:    input.DocumentType,
+		Country: input.DocumentCountry,
+		Number:  input.PassportNumber,
+	})
+	if err != nil {
+		return pgdb.CreateUserInput{}, err
+	}
+	if err := s.checkDocumentFree(ctx, doc, 0); err != nil {
+		return pgdb.CreateUserInput{}, err
+	}
 
-	client := http.Client{
-		Timeout: 4 * time.Second,
+	data := pgdb.CreateUserInput{
+		DocumentType:    doc.Type,
+		DocumentCountry: doc.Country,
+		PassportNumber:  doc.Number,
+		Email:           normalizeEmail(input.Email),
+	}
+	if input.Name != "" && input.Surname != "" {
+		data.Name = input.Name
+		data.Surname = input.Surname
+		data.Patronymic = input.Patronymic
+		data.Address = input.Address
+		data.EnrichmentStatus = model.EnrichmentStatusManual
+		return data, nil
+	}
+	if !s.documents.Lookup(doc.Type) {
+		return data, ErrProfileRequired
 	}
 
-	res, err := client.Get(s.userApiURl + "/info" + queryParams)
+	data.EnrichmentStatus = model.EnrichmentStatusEnriched
+	data, err = s.enrich(ctx, data)
 	if err != nil {
-		return 0, fmt.Errorf("UserService.CreateUser - http.Get: %v", err)
+		return data, fmt.Errorf("UserService.prepareUser - s.enrich: %w", err)
 	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("UserService.CreateUser - http.Get: %v", err)
+	return data, nil
+}
+
+// checkDocumentFree returns ErrAlreadyExists when a user other than userID holds the document.
+func (s *UserService) checkDocumentFree(ctx context.Context, doc document.Document, userID int) error {
+	user, err := s.repo.GeUsertByPassportNumber(ctx, doc)
+	if err == nil && user.ID != userID {
+		return repoerr.ErrAlreadyExists
 	}
+	if err != nil && !errors.Is(err, repoerr.ErrNotFound) {
+		return err
+	}
+	return nil
+}
 
-	type userInfoResponse struct {
-		Surname    string `json:"surname"`
-		Name       string `json:"name"`
-		Patronymic string `json:"patronymic"`
-		Address    string `json:"address"`
+// enrich fills the profile from the providers, an unavailable provider leaves the user pending.
+func (s *UserService) enrich(ctx context.Context, data pgdb.CreateUserInput) (pgdb.CreateUserInput, error) {
+	info, err := s.fetchInfo(ctx, data.PassportNumber)
+	switch {
+	case errors.Is(err, peopleinfo.ErrUnavailable):
+		data.EnrichmentStatus = model.EnrichmentStatusPending
+	case err != nil:
+		return data, err
+	default:
+		data.Name = info.Name
+		data.Surname = info.Surname
+		data.Patronymic = info.Patronymic
+		data.Address = info.Address
 	}
-	var info userInfoResponse
-	err = json.NewDecoder(res.Body).Decode(&info)
+	return data, nil
+}
+
+func (s *UserService) createUser(ctx context.Context, data pgdb.CreateUserInput) (model.User, error) {
+	var user model.User
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ID, err := s.repo.CreateUser(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.repo.GetUser(ctx, ID, false)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityUser, ID, model.AuditActionCreate, nil, user)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("UserService.CreateUser - pgdb.Unmarshal: %v", err)
+		return model.User{}, err
 	}
+	return user, nil
+}
 
-	return s.repo.CreateUser(ctx, pgdb.CreateUserInput{
-		Name:           info.Name,
-		Patronymic:     info.Patronymic,
-		PassportNumber: passportNumber,
-		Address:        info.Address,
-	})
+// fetchInfo looks up a russian passport holder, the only document type the providers support. A number
+// that is not "series number", e.g. the emptied one of an anonymized user, is not found.
+func (s *UserService) fetchInfo(ctx context.Context, passportNumber string) (peopleinfo.Info, error) {
+	passportNums := strings.Split(passportNumber, " ")
+	if len(passportNums) != 2 {
+		return peopleinfo.Info{}, peopleinfo.ErrNotFound
+	}
+	return s.peopleInfo.GetInfo(ctx, passportNums[0], passportNums[1])
 }
 
-func (s *UserService) GetUser(ctx context.Context, ID int) (model.User, error) {
-	return s.repo.GetUser(ctx, ID)
+// EnrichPendingUsers retries the enrichment providers for users created while they were unavailable.
+// The run stops at the first unavailability, the next run starts over from the oldest pending user.
+func (s *UserService) EnrichPendingUsers(ctx context.Context) error {
+	users, err := s.repo.ListUsersByEnrichmentStatus(ctx, model.EnrichmentStatusPending, enrichmentBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		data := pgdb.UpdateUserInput{}
+		info, err := s.fetchInfo(ctx, user.PassportNumber)
+		switch {
+		case errors.Is(err, peopleinfo.ErrUnavailable):
+			return nil
+		case err != nil:
+			status := model.EnrichmentStatusFailed
+			data.EnrichmentStatus = &status
+		default:
+			status := model.EnrichmentStatusEnriched
+			data = pgdb.UpdateUserInput{
+				Name:             &info.Name,
+				Surname:          &info.Surname,
+				Patronymic:       &info.Patronymic,
+				Address:          &info.Address,
+				EnrichmentStatus: &status,
+			}
+		}
+
+		if err := s.updateUser(ctx, user, data); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-type ListUsersFilter struct {
-	Name           string
-	Surname        string
-	Patronymic     string
-	PassportNumber string
-	Address        string
-	Limit          int
-	Offset         int
+func (s *UserService) GetUser(ctx context.Context, ID int, includeDeleted bool) (model.User, error) {
+	if includeDeleted && !HasPermission(ctx, PermissionAdmin) {
+		return model.User{}, ErrForbidden
+	}
+	return s.repo.GetUser(ctx, ID, includeDeleted)
 }
 
-func (s *UserService) ListUsers(ctx context.Context, filter ListUsersFilter) ([]model.User, error) {
-	return s.repo.ListUsersPagination(ctx, filter.Name, filter.Surname, filter.Patronymic, filter.PassportNumber, filter.Address, filter.Limit, filter.Offset)
+func (s *UserService) ListUsers(ctx context.Context, filter pgdb.ListUsersFilter) (pgdb.Page[model.User], error) {
+	filter, err := s.listFilter(ctx, filter)
+	if err != nil {
+		return pgdb.Page[model.User]{}, err
+	}
+	return s.repo.ListUsersPagination(ctx, filter)
+}
+
+// ExportUsers streams the users to fn, see ListUsers.
+func (s *UserService) ExportUsers(ctx context.Context, filter pgdb.ListUsersFilter, fn func(model.User) error) error {
+	filter, err := s.listFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return s.repo.ForEachUser(ctx, filter, fn)
+}
+
+func (s *UserService) listFilter(ctx context.Context, filter pgdb.ListUsersFilter) (pgdb.ListUsersFilter, error) {
+	if filter.IncludeDeleted && !HasPermission(ctx, PermissionAdmin) {
+		return filter, ErrForbidden
+	}
+	if len(filter.Statuses) == 0 {
+		// former employees are only listed on request
+		filter.Statuses = []string{model.UserStatusActive, model.UserStatusSuspended}
+	}
+	if filter.PassportNumber != "" {
+		// the document is matched through its blind index, so it has to be normalized the way it was stored
+		doc, err := s.documents.Validate(document.Document{
+			Type:    filter.DocumentType,
+			Country: filter.DocumentCountry,
+			Number:  filter.PassportNumber,
+		})
+		if err != nil {
+			return filter, err
+		}
+		filter.DocumentType, filter.DocumentCountry, filter.PassportNumber = doc.Type, doc.Country, doc.Number
+	}
+	return filter, nil
 }
 
 func (s *UserService) UpdateUser(ctx context.Context, ID int, data pgdb.UpdateUserInput) error {
-	_, err := s.repo.GetUser(ctx, ID)
+	before, err := s.repo.GetUser(ctx, ID, false)
 	if err != nil {
 		return err
 	}
-	return s.repo.UpdateUser(ctx, ID, data)
+
+	if data.PassportNumber != nil || data.DocumentType != nil || data.DocumentCountry != nil {
+		doc := document.Document{Type: before.DocumentType, Country: before.DocumentCountry, Number: before.PassportNumber}
+		if data.DocumentType != nil {
+			// another type brings its own default country
+			doc.Type, doc.Country = *data.DocumentType, ""
+		}
+		if data.DocumentCountry != nil {
+			doc.Country = *data.DocumentCountry
+		}
+		if data.PassportNumber != nil {
+			doc.Number = *data.PassportNumber
+		}
+
+		doc, err = s.documents.Validate(doc)
+		if err != nil {
+			return err
+		}
+		if err := s.checkDocumentFree(ctx, doc, ID); err != nil {
+			return err
+		}
+		data.DocumentType, data.DocumentCountry, data.PassportNumber = &doc.Type, &doc.Country, &doc.Number
+		if !s.documents.Lookup(doc.Type) && data.EnrichmentStatus == nil {
+			// keep the profile away from enrichment and sync, the providers can't look the document up
+			status := model.EnrichmentStatusManual
+			data.EnrichmentStatus = &status
+		}
+	}
+	if data.Email != nil {
+		email := normalizeEmail(*data.Email)
+		data.Email = &email
+	}
+	return s.updateUser(ctx, before, data)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *UserService) updateUser(ctx context.Context, before model.User, data pgdb.UpdateUserInput) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.repo.UpdateUser(ctx, before.ID, data)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetUser(ctx, before.ID, false)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityUser, before.ID, model.AuditActionUpdate, before, after)
+	})
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id int) error {
-	return s.repo.DeleteUser(ctx, id)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetUser(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		err = s.repo.DeleteUser(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetUser(ctx, id, true)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityUser, id, model.AuditActionDelete, before, after)
+	})
+}
+
+func (s *UserService) RestoreUser(ctx context.Context, id int) error {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return ErrForbidden
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetUser(ctx, id, true)
+		if err != nil {
+			return err
+		}
+		err = s.repo.RestoreUser(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetUser(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityUser, id, model.AuditActionRestore, before, after)
+	})
+}
+
+// ExportUser collects the profile, tasks and audit history of the user, deleted ones included.
+func (s *UserService) ExportUser(ctx context.Context, id int) (model.UserExport, error) {
+	if !HasPermission(ctx, PermissionPIIRead) {
+		return model.UserExport{}, ErrForbidden
+	}
+
+	user, err := s.repo.GetUser(ctx, id, true)
+	if err != nil {
+		return model.UserExport{}, err
+	}
+	var tasks []model.Task
+	filter := pgdb.ListTasksFilter{IncludeDeleted: true, PageRequest: pgdb.PageRequest{Sort: "id", Limit: exportPageLimit}}
+	for {
+		page, err := s.tasks.ListTasks(ctx, id, filter)
+		if err != nil {
+			return model.UserExport{}, err
+		}
+		tasks = append(tasks, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	records, err := s.audit.ListEntityRecords(ctx, model.AuditEntityUser, []int{id})
+	if err != nil {
+		return model.UserExport{}, err
+	}
+	if len(tasks) > 0 {
+		taskIDs := make([]int, 0, len(tasks))
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		taskRecords, err := s.audit.ListEntityRecords(ctx, model.AuditEntityTask, taskIDs)
+		if err != nil {
+			return model.UserExport{}, err
+		}
+		records = append(records, taskRecords...)
+	}
+
+	return model.UserExport{
+		User:         user,
+		Tasks:        tasks,
+		AuditRecords: records,
+	}, nil
+}
+
+// AnonymizeUser scrubs the personal data of the user and its audit history, unlike DeleteUser
+// the tasks stay attached so their durations keep counting in aggregate reports. Both are scrubbed
+// in one transaction, a failed call leaves the user as it was and can be retried.
+func (s *UserService) AnonymizeUser(ctx context.Context, id int) error {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return ErrForbidden
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := s.repo.GetUser(ctx, id, true)
+		if err != nil {
+			return err
+		}
+		if user.AnonymizedAt != nil {
+			return ErrUserAnonymized
+		}
+
+		err = s.repo.AnonymizeUser(ctx, id)
+		if errors.Is(err, repoerr.ErrNotFound) {
+			// anonymized concurrently since the lookup
+			return ErrUserAnonymized
+		}
+		if err != nil {
+			return err
+		}
+		err = s.audit.Scrub(ctx, model.AuditEntityUser, id)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityUser, id, model.AuditActionAnonymize, nil, nil)
+	})
 }
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/document"
+)
+
+const (
+	ImportStatusCreated   = "created"
+	ImportStatusValid     = "valid" // dry run, the row would be created
+	ImportStatusExists    = "exists"
+	ImportStatusDuplicate = "duplicate"
+	ImportStatusInvalid   = "invalid"
+	ImportStatusFailed    = "failed"
+
+	defaultImportWorkers = 8
+)
+
+// ImportUserRow is an import row, rows with a name and surname are taken as is, the rest are enriched.
+type ImportUserRow struct {
+	Line int
+	CreateUserInput
+}
+
+type ImportUserResult struct {
+	Line             int
+	Status           string
+	ID               int
+	EnrichmentStatus string
+	Error            string
+}
+
+// ImportMaxRows is the most rows ImportUsers accepts, readers of the import stop after it.
+func (s *UserService) ImportMaxRows() int {
+	return s.importMaxRows
+}
+
+// ImportUsers creates users from rows with at most importWorkers rows processed concurrently.
+// In the dry run rows are validated and enriched, but nothing is written.
+func (s *UserService) ImportUsers(ctx context.Context, rows []ImportUserRow, dryRun bool) ([]ImportUserResult, error) {
+	if len(rows) > s.importMaxRows {
+		return nil, ErrImportTooLarge
+	}
+
+	results := make([]ImportUserResult, len(rows))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.importWorkers, len(rows)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.importUser(ctx, rows[i], dryRun)
+			}
+		}()
+	}
+
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		key := row.DocumentType + ":" + row.DocumentCountry + ":" + row.PassportNumber
+		if line, ok := seen[key]; ok {
+			results[i] = ImportUserResult{
+				Line:   row.Line,
+				Status: ImportStatusDuplicate,
+				Error:  fmt.Sprintf("document repeats line %d", line),
+			}
+			continue
+		}
+		seen[key] = row.Line
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+func (s *UserService) importUser(ctx context.Context, row ImportUserRow, dryRun bool) ImportUserResult {
+	result := ImportUserResult{Line: row.Line}
+	fail := func(err error) ImportUserResult {
+		result.Status = ImportStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	data, err := s.prepareUser(ctx, row.CreateUserInput)
+	switch {
+	case errors.Is(err, repoerr.ErrAlreadyExists):
+		result.Status = ImportStatusExists
+		return result
+	case errors.Is(err, document.ErrInvalid) || errors.Is(err, ErrProfileRequired):
+		result.Status = ImportStatusInvalid
+		result.Error = err.Error()
+		return result
+	case err != nil:
+		return fail(err)
+	}
+	result.EnrichmentStatus = data.EnrichmentStatus
+
+	if dryRun {
+		result.Status = ImportStatusValid
+		return result
+	}
+	user, err := s.createUser(ctx, data)
+	if err != nil {
+		return fail(err)
+	}
+	result.Status = ImportStatusCreated
+	result.ID = user.ID
+	return result
+}
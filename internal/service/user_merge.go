@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"time-tracker/internal/model"
+)
+
+const (
+	defaultDuplicateMinScore = 0.5
+	duplicateCandidatesLimit = 20
+)
+
+// FindDuplicates returns users that are likely the same person as the user with the given id.
+// A minScore outside of (0, 1] falls back to the default.
+func (s *UserService) FindDuplicates(ctx context.Context, id int, minScore float64) ([]model.DuplicateCandidate, error) {
+	user, err := s.repo.GetUser(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if minScore <= 0 || minScore > 1 {
+		minScore = defaultDuplicateMinScore
+	}
+	return s.repo.FindDuplicateCandidates(ctx, user, minScore, duplicateCandidatesLimit)
+}
+
+// MergeUsers moves the tasks of the source user to the target and deletes the source, invoiced tasks stay
+// with the source. The merge is recorded on both users and on every moved task in the same transaction.
+func (s *UserService) MergeUsers(ctx context.Context, sourceID, targetID int) error {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return ErrForbidden
+	}
+	if sourceID == targetID {
+		return ErrMergeSameUser
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetUser(ctx, sourceID, false)
+		if err != nil {
+			return err
+		}
+		taskIDs, err := s.repo.MergeUsers(ctx, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetUser(ctx, sourceID, true)
+		if err != nil {
+			return err
+		}
+		err = s.audit.Record(ctx, model.AuditEntityUser, sourceID, model.AuditActionMerge, before, after)
+		if err != nil {
+			return err
+		}
+		err = s.audit.Record(ctx, model.AuditEntityUser, targetID, model.AuditActionMerge, nil, map[string]any{
+			"merged_user_id": sourceID,
+			"task_ids":       taskIDs,
+		})
+		if err != nil {
+			return err
+		}
+		for _, taskID := range taskIDs {
+			err = s.audit.Record(ctx, model.AuditEntityTask, taskID, model.AuditActionMerge,
+				map[string]int{"user_id": sourceID}, map[string]int{"user_id": targetID},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
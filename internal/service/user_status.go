@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/pgdb"
+)
+
+type ChangeUserStatusInput struct {
+	Status string
+	// EffectiveFrom may be in the past or the future, the change takes effect now when it is zero
+	EffectiveFrom time.Time
+	Reason        string
+}
+
+// ChangeUserStatus adds a change to the lifecycle history of the user.
+func (s *UserService) ChangeUserStatus(ctx context.Context, id int, input ChangeUserStatusInput) error {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return ErrForbidden
+	}
+	if input.EffectiveFrom.IsZero() {
+		input.EffectiveFrom = time.Now()
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetUser(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		_, err = s.repo.CreateUserStatusChange(ctx, pgdb.CreateUserStatusChangeInput{
+			UserID:        id,
+			Status:        input.Status,
+			EffectiveFrom: input.EffectiveFrom,
+			Reason:        input.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, model.AuditEntityUser, id, model.AuditActionStatus,
+			map[string]any{"status": before.Status},
+			map[string]any{"status": input.Status, "effective_from": input.EffectiveFrom, "reason": input.Reason},
+		)
+	})
+}
+
+func (s *UserService) ListUserStatusChanges(ctx context.Context, id int) ([]model.UserStatusChange, error) {
+	_, err := s.repo.GetUser(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListUserStatusChanges(ctx, id)
+}
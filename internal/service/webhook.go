@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/pkg/publish"
+	"time-tracker/pkg/webhook"
+)
+
+const (
+	webhookSecretBytes   = 32
+	webhookDeliveryLimit = 100
+)
+
+// WebhookSender posts a signed payload, see webhook.Client.
+type WebhookSender interface {
+	Send(ctx context.Context, r webhook.Request) (int, error)
+}
+
+type WebhookService struct {
+	repo        repository.Webhook
+	tx          repository.Transactor
+	audit       Audit
+	sender      WebhookSender
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	lease       time.Duration // of claimed deliveries, longer than a delivery attempt takes
+	batchSize   int
+	retention   time.Duration // of delivered and failed deliveries
+}
+
+func NewWebhookService(repo repository.Webhook, tx repository.Transactor, audit Audit, sender WebhookSender, maxAttempts int, backoffBase, backoffMax, lease time.Duration, batchSize int, retention time.Duration) *WebhookService {
+	return &WebhookService{repo, tx, audit, sender, maxAttempts, backoffBase, backoffMax, lease, batchSize, retention}
+}
+
+// CreateWebhookInput subscribes URL to Events, every event when it is empty. A secret is generated when none
+// is given.
+type CreateWebhookInput struct {
+	URL    string
+	Events []string
+	Secret string
+}
+
+// CreateSubscription returns the subscription with its secret, which is not shown afterwards.
+func (s *WebhookService) CreateSubscription(ctx context.Context, input CreateWebhookInput) (model.WebhookSubscription, error) {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return model.WebhookSubscription{}, ErrForbidden
+	}
+	if input.Secret == "" {
+		raw := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return model.WebhookSubscription{}, fmt.Errorf("WebhookService.CreateSubscription - rand.Read: %v", err)
+		}
+		input.Secret = base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	var subscription model.WebhookSubscription
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ID, err := s.repo.CreateSubscription(ctx, pgdb.CreateWebhookSubscriptionInput{
+			URL:    input.URL,
+			Events: input.Events,
+			Secret: input.Secret,
+		})
+		if err != nil {
+			return err
+		}
+		subscription, err = s.repo.GetSubscription(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityWebhook, ID, model.AuditActionCreate, nil, withoutSecret(subscription))
+	})
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	return subscription, nil
+}
+
+// ListSubscriptions returns the subscriptions without their secrets.
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return nil, ErrForbidden
+	}
+	subscriptions, err := s.repo.ListSubscriptions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i] = withoutSecret(subscriptions[i])
+	}
+	return subscriptions, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, ID int) error {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return ErrForbidden
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetSubscription(ctx, ID)
+		if err != nil {
+			return err
+		}
+		err = s.repo.DeleteSubscription(ctx, ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditEntityWebhook, ID, model.AuditActionDelete, withoutSecret(before), nil)
+	})
+}
+
+func withoutSecret(subscription model.WebhookSubscription) model.WebhookSubscription {
+	subscription.Secret = ""
+	return subscription
+}
+
+// ListDeliveries returns the latest deliveries to the subscription, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID int) ([]model.WebhookDelivery, error) {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return nil, ErrForbidden
+	}
+	_, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, webhookDeliveryLimit)
+}
+
+// Redeliver queues the payload of the delivery again as a new delivery, the event keeps its ID so that
+// receivers can deduplicate it.
+func (s *WebhookService) Redeliver(ctx context.Context, subscriptionID, deliveryID int) (model.WebhookDelivery, error) {
+	if !HasPermission(ctx, PermissionAdmin) {
+		return model.WebhookDelivery{}, ErrForbidden
+	}
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return model.WebhookDelivery{}, repoerr.ErrNotFound
+	}
+	IDs, err := s.repo.CreateDeliveries(ctx, pgdb.CreateWebhookDeliveriesInput{
+		SubscriptionIDs: []int{subscriptionID},
+		EventID:         delivery.EventID,
+		Event:           delivery.Event,
+		Payload:         delivery.Payload,
+	})
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return s.repo.GetDelivery(ctx, IDs[0])
+}
+
+// WebhookPublisher fans the outbox events out to the webhook subscriptions receiving them as pending
+// deliveries, WebhookService.DeliverPending sends them.
+type WebhookPublisher struct {
+	repo repository.Webhook
+}
+
+func NewWebhookPublisher(repo repository.Webhook) *WebhookPublisher {
+	return &WebhookPublisher{repo}
+}
+
+// Publish keys the webhook event by the outbox event ID, so that receivers can deduplicate a repeated one.
+func (p *WebhookPublisher) Publish(ctx context.Context, m publish.Message) error {
+	subscriptions, err := p.repo.ListSubscriptions(ctx, m.Event)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	payload := model.WebhookEvent{ID: strconv.FormatInt(m.ID, 10), Event: m.Event, CreatedAt: m.CreatedAt, Data: m.Payload}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("WebhookPublisher.Publish - json.Marshal: %v", err)
+	}
+
+	input := pgdb.CreateWebhookDeliveriesInput{EventID: payload.ID, Event: m.Event, Payload: body}
+	for _, subscription := range subscriptions {
+		input.SubscriptionIDs = append(input.SubscriptionIDs, subscription.ID)
+	}
+	_, err = p.repo.CreateDeliveries(ctx, input)
+	return err
+}
+
+// DeliverPending sends the deliveries due by now. A failed attempt is retried with exponential backoff until
+// the deliveries run out of attempts. Delivered and failed deliveries are purged after the retention period.
+func (s *WebhookService) DeliverPending(ctx context.Context) error {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.batchSize, s.lease)
+	if err != nil {
+		return err
+	}
+
+	subscriptions := make(map[int]model.WebhookSubscription)
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if errors.Is(err, repoerr.ErrNotFound) {
+				// deleted with its deliveries meanwhile
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		status, err := s.sender.Send(ctx, webhook.Request{
+			URL:        subscription.URL,
+			Secret:     subscription.Secret,
+			Event:      delivery.Event,
+			DeliveryID: strconv.Itoa(delivery.ID),
+			Body:       delivery.Payload,
+		})
+		now := time.Now()
+		data := pgdb.UpdateWebhookDeliveryInput{Attempts: delivery.Attempts + 1}
+		if status != 0 {
+			data.ResponseStatus = &status
+		}
+		switch {
+		case err == nil:
+			data.Status = model.WebhookDeliveryDelivered
+			data.DeliveredAt = &now
+		case data.Attempts >= s.maxAttempts:
+			data.Status = model.WebhookDeliveryFailed
+			data.LastError = err.Error()
+		default:
+			next := now.Add(s.backoff(data.Attempts))
+			data.Status = model.WebhookDeliveryPending
+			data.NextAttemptAt = &next
+			data.LastError = err.Error()
+		}
+		if err := s.repo.UpdateDelivery(ctx, delivery.ID, data); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.repo.PurgeDeliveries(ctx, time.Now().Add(-s.retention))
+	return err
+}
+
+// backoff returns the delay after the given failed attempt, doubling from the base up to the max.
+func (s *WebhookService) backoff(attempt int) time.Duration {
+	delay := s.backoffBase
+	for i := 1; i < attempt && delay < s.backoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, s.backoffMax)
+}
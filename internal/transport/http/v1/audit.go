@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"net/http"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditRoutes struct {
+	service service.Audit
+}
+
+func newAuditRoutes(handler *gin.RouterGroup, service service.Audit) {
+	r := &AuditRoutes{service}
+	handler.GET("", r.getList)
+}
+
+type getAuditListInput struct {
+	Entity string `json:"entity" form:"entity" binding:"required,oneof=user task project invoice client webhook"`
+	ID     int    `json:"id" form:"id" binding:"required"`
+	Offset int    `json:"offset,omitempty" form:"offset"`
+	Limit  int    `json:"limit,omitempty" form:"limit"`
+}
+
+// @Summary Получение журнала изменений
+// @Description Audit log of an entity. Personal data in diffs is masked without the pii:read permission
+// @Tags Audit / Журнал изменений
+// @Accept json
+// @Produce json
+// @Param input query getAuditListInput true "Filter"
+// @Success 200 {array} model.AuditRecord
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/audit [get]
+func (r *AuditRoutes) getList(c *gin.Context) {
+	var input getAuditListInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := r.service.ListRecords(c, pgdb.ListAuditRecordsFilter{
+		Entity:   input.Entity,
+		EntityID: input.ID,
+		Limit:    input.Limit,
+		Offset:   input.Offset,
+	})
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(items) == 0 {
+		items = []model.AuditRecord{}
+	}
+	newResponse(c, http.StatusOK, items)
+}
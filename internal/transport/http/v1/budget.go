@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetRoutes struct {
+	service service.Budget
+}
+
+func newBudgetRoutes(handler *gin.RouterGroup, service service.Budget) {
+	r := &BudgetRoutes{service}
+	handler.PUT(":id/budget", r.set)
+	handler.GET(":id/budget", r.get)
+	handler.DELETE(":id/budget", r.delete)
+	handler.GET(":id/budget/burndown", r.burndown)
+}
+
+type setBudgetInput struct {
+	Kind   string  `json:"kind" binding:"required,oneof=hours money"`
+	Period string  `json:"period" binding:"required,oneof=lifetime week month quarter year"`
+	Limit  float64 `json:"limit" binding:"required,gt=0"`  // hours or minor currency units
+	Rate   int64   `json:"rate,omitempty" binding:"gte=0"` // per hour in minor currency units, money budgets only
+}
+
+type setBudgetResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Установка бюджета проекта
+// @Description Creates or replaces the budget of the project. Hours budgets limit the tracked hours, money budgets
+// @Description the time billed at rate, by default the default rate of the project's client, in minor currency units.
+// @Description The limit applies to every week, month, quarter or year or to the project lifetime. Alerts are sent
+// @Description when the completed tasks of a period reach the BUDGET_THRESHOLDS percentages of the limit
+// @Tags Projects / Проекты
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param input body setBudgetInput true "Budget input"
+// @Success 200 {object} setBudgetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/{id}/budget [put]
+func (r *BudgetRoutes) set(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input setBudgetInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.Kind == model.BudgetKindMoney && input.Limit != math.Trunc(input.Limit) {
+		newErrorResponse(c, http.StatusBadRequest, "money limit must be in whole minor currency units")
+		return
+	}
+
+	err = r.service.SetBudget(c, id, service.SetBudgetInput{
+		Kind:   input.Kind,
+		Period: input.Period,
+		Limit:  input.Limit,
+		Rate:   input.Rate,
+	})
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrRateRequired) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, setBudgetResponse{Success: true})
+}
+
+// @Summary Получение бюджета проекта
+// @Description Budget of the project, the limit is in minutes for hours budgets
+// @Tags Projects / Проекты
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} model.ProjectBudget
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/{id}/budget [get]
+func (r *BudgetRoutes) get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	budget, err := r.service.GetBudget(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, budget)
+}
+
+type deleteBudgetResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Удаление бюджета проекта
+// @Description Delete the budget of the project
+// @Tags Projects / Проекты
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} deleteBudgetResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/{id}/budget [delete]
+func (r *BudgetRoutes) delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	err = r.service.DeleteBudget(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, deleteBudgetResponse{Success: true})
+}
+
+type burndownInput struct {
+	Date time.Time `json:"date,omitempty" time_format:"2006-01-02T15:04:05Z07:00" form:"date"` // now by default
+}
+
+// @Summary Сгорание бюджета проекта
+// @Description Consumption of the budget period containing date day by day up to that day, in minutes for hours
+// @Description budgets. ideal is the remaining limit at an even pace, lifetime budgets start at the first task
+// @Tags Projects / Проекты
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param input query burndownInput true "Filter"
+// @Success 200 {object} model.BudgetBurndown
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/{id}/budget/burndown [get]
+func (r *BudgetRoutes) burndown(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input burndownInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.Date.IsZero() {
+		input.Date = time.Now()
+	}
+
+	burndown, err := r.service.Burndown(c, id, input.Date)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, burndown)
+}
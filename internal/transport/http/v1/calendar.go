@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/internal/service"
+	"time-tracker/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CalendarRoutes struct {
+	service service.Calendar
+}
+
+func newCalendarRoutes(handler *gin.RouterGroup, service service.Calendar) {
+	r := &CalendarRoutes{service}
+	handler.POST(":id/calendar-token", r.issueToken)
+	handler.GET(":id/calendar.ics", r.feed)
+}
+
+type calendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"` // path of the feed to subscribe to
+}
+
+// @Summary Ссылка на календарь пользователя
+// @Description Issue a new token for the iCalendar feed of the user, the previous subscription link stops working.
+// @Description Only the user (X-Actor "user:{id}") or an admin
+// @Tags Calendar / Календарь
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} calendarTokenResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/calendar-token [post]
+func (r *CalendarRoutes) issueToken(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	token, err := r.service.IssueCalendarToken(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, calendarTokenResponse{
+		Token: token,
+		URL:   fmt.Sprintf("/api/v1/users/%d/calendar.ics?token=%s", id, token),
+	})
+}
+
+type calendarFeedInput struct {
+	Token string `form:"token" binding:"required"`
+	// Days is the rolling window of the feed, the configured default when omitted
+	Days int `form:"days" binding:"omitempty,gt=0"`
+}
+
+// @Summary Календарь пользователя
+// @Description iCalendar feed with a VEVENT per task started within the rolling window, for calendar app subscriptions
+// @Tags Calendar / Календарь
+// @Produce text/calendar
+// @Param id path int true "User ID"
+// @Param token query string true "Token from calendar-token"
+// @Param days query int false "Window in days before now"
+// @Success 200 {file} file
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/calendar.ics [get]
+func (r *CalendarRoutes) feed(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input calendarFeedInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	feed, err := r.service.CalendarFeed(c, id, input.Token, time.Duration(input.Days)*24*time.Hour)
+	if err != nil {
+		// an unknown user is reported as an invalid token, so that the feed does not reveal which users exist
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusUnauthorized, service.ErrInvalidToken.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	w, err := ical.NewWriter(c.Writer, feed.Name, feed.Location, feed.From, feed.To)
+	if err == nil {
+		for _, event := range feed.Events {
+			if err = w.WriteEvent(event); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		logrus.Errorf("calendar feed of user %d: %v", id, err)
+	}
+}
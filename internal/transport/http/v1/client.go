@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientRoutes struct {
+	service service.Client
+}
+
+func newClientRoutes(handler *gin.RouterGroup, service service.Client) {
+	r := &ClientRoutes{service}
+	handler.POST("", r.create)
+	handler.GET("", r.getList)
+	handler.GET(":id", r.get)
+	handler.PATCH(":id", r.update)
+}
+
+type createClientInput struct {
+	Name        string `json:"name" binding:"required,max=256"`
+	ContactName string `json:"contactName,omitempty" binding:"max=256"`
+	Email       string `json:"email,omitempty" binding:"omitempty,email,max=256"`
+	Phone       string `json:"phone,omitempty" binding:"max=64"`
+	Address     string `json:"address,omitempty" binding:"max=1024"`
+	Currency    string `json:"currency" binding:"required,len=3,uppercase"`
+	DefaultRate int64  `json:"defaultRate,omitempty" binding:"gte=0"` // per hour in minor currency units
+}
+
+type createClientResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Создание клиента
+// @Description Create Client, names are unique in any case. The default rate and currency are used for invoices
+// @Description of the client's projects
+// @Tags Clients / Клиенты
+// @Accept json
+// @Produce json
+// @Param input body createClientInput true "Client input"
+// @Success 200 {object} createClientResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/clients [post]
+func (r *ClientRoutes) create(c *gin.Context) {
+	var input createClientInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := r.service.CreateClient(c, pgdb.CreateClientInput{
+		Name:        input.Name,
+		ContactName: input.ContactName,
+		Email:       input.Email,
+		Phone:       input.Phone,
+		Address:     input.Address,
+		Currency:    input.Currency,
+		DefaultRate: input.DefaultRate,
+	})
+	if err != nil {
+		if errors.Is(err, repoerr.ErrAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, createClientResponse{ID: id})
+}
+
+// @Summary Получение списка клиентов
+// @Description Client list ordered by name
+// @Tags Clients / Клиенты
+// @Produce json
+// @Success 200 {array} model.Client
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/clients [get]
+func (r *ClientRoutes) getList(c *gin.Context) {
+	items, err := r.service.ListClients(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Получение клиента
+// @Description Get Client
+// @Tags Clients / Клиенты
+// @Produce json
+// @Param id path int true "Client ID"
+// @Success 200 {object} model.Client
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/clients/{id} [get]
+func (r *ClientRoutes) get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	client, err := r.service.GetClient(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, client)
+}
+
+type updateClientInput struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=256"`
+	ContactName *string `json:"contactName,omitempty" binding:"omitempty,max=256"`
+	Email       *string `json:"email,omitempty" binding:"omitempty,email,max=256"`
+	Phone       *string `json:"phone,omitempty" binding:"omitempty,max=64"`
+	Address     *string `json:"address,omitempty" binding:"omitempty,max=1024"`
+	Currency    *string `json:"currency,omitempty" binding:"omitempty,len=3,uppercase"`
+	DefaultRate *int64  `json:"defaultRate,omitempty" binding:"omitempty,gte=0"`
+}
+
+type updateClientResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Изменение клиента
+// @Description Update Client, omitted fields are kept
+// @Tags Clients / Клиенты
+// @Accept json
+// @Produce json
+// @Param id path int true "Client ID"
+// @Param input body updateClientInput true "Client input"
+// @Success 200 {object} updateClientResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/clients/{id} [patch]
+func (r *ClientRoutes) update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input updateClientInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = r.service.UpdateClient(c, id, pgdb.UpdateClientInput{
+		Name:        input.Name,
+		ContactName: input.ContactName,
+		Email:       input.Email,
+		Phone:       input.Phone,
+		Address:     input.Address,
+		Currency:    input.Currency,
+		DefaultRate: input.DefaultRate,
+	})
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, repoerr.ErrAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, updateClientResponse{Success: true})
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"time-tracker/pkg/pii"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -11,6 +13,6 @@ type errorResponse struct {
 }
 
 func newErrorResponse(c *gin.Context, statusCode int, message string) {
-	logrus.Error(message)
+	logrus.Error(pii.Scrub(message))
 	c.AbortWithStatusJSON(statusCode, errorResponse{statusCode, message})
 }
\ No newline at end of file
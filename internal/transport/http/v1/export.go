@@ -0,0 +1,218 @@
+package v1
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"time-tracker/internal/service"
+	"time-tracker/pkg/pii"
+	"time-tracker/pkg/xlsx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// exportInput selects the spreadsheet columns of list endpoints requested with a CSV or XLSX Accept header.
+type exportInput struct {
+	Columns string `json:"columns,omitempty" form:"columns"`
+}
+
+// exportColumn is a spreadsheet column, pii columns are masked for callers without the pii:read permission.
+type exportColumn[T any] struct {
+	name  string
+	value func(T) any
+	pii   bool
+}
+
+// exportFormat returns the spreadsheet type asked for in the Accept header, empty when JSON is expected.
+func exportFormat(c *gin.Context) string {
+	switch format := c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeXLSX); format {
+	case mimeCSV, mimeXLSX:
+		return format
+	}
+	return ""
+}
+
+// selectColumns picks the columns named in the comma separated list in its order, every column when it is empty.
+func selectColumns[T any](columns []exportColumn[T], names string) ([]exportColumn[T], error) {
+	if names == "" {
+		return columns, nil
+	}
+
+	var selected []exportColumn[T]
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, column := range columns {
+			if column.name == name {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// hours formats a duration in minutes for spreadsheets.
+func hours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+type tableWriter interface {
+	WriteRow(cells []any) error
+	Close() error
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (w csvWriter) WriteRow(cells []any) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// tableExport streams rows to the response in the negotiated format. The response is started by the first row,
+// so errors returned before it can still be answered with an error status.
+type tableExport[T any] struct {
+	c        *gin.Context
+	format   string
+	filename string // without extension
+	columns  []exportColumn[T]
+	mask     bool
+	w        tableWriter
+}
+
+func newTableExport[T any](c *gin.Context, format, filename string, columns []exportColumn[T]) *tableExport[T] {
+	return &tableExport[T]{
+		c:        c,
+		format:   format,
+		filename: filename,
+		columns:  columns,
+		mask:     !service.HasPermission(c, service.PermissionPIIRead),
+	}
+}
+
+func (e *tableExport[T]) started() bool {
+	return e.w != nil
+}
+
+func (e *tableExport[T]) start() error {
+	contentType, extension := mimeCSV+"; charset=utf-8", "csv"
+	if e.format == mimeXLSX {
+		contentType, extension = mimeXLSX, "xlsx"
+	}
+	e.c.Header("Content-Type", contentType)
+	e.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.filename, extension))
+
+	if e.format == mimeXLSX {
+		w, err := xlsx.NewWriter(e.c.Writer, e.filename)
+		if err != nil {
+			return err
+		}
+		e.w = w
+	} else {
+		e.w = csvWriter{csv.NewWriter(e.c.Writer)}
+	}
+
+	header := make([]any, len(e.columns))
+	for i, column := range e.columns {
+		header[i] = column.name
+	}
+	return e.w.WriteRow(header)
+}
+
+func (e *tableExport[T]) write(item T) error {
+	if !e.started() {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	cells := make([]any, len(e.columns))
+	for i, column := range e.columns {
+		cell := cellValue(column.value(item))
+		if s, ok := cell.(string); ok {
+			if column.pii && e.mask && s != "" {
+				s = pii.Mask(s)
+			}
+			if e.format != mimeXLSX {
+				s = escapeFormula(s)
+			}
+			cell = s
+		}
+		cells[i] = cell
+	}
+	return e.w.WriteRow(cells)
+}
+
+// finish completes the file. err is the error the rows were streamed with, the status is already sent
+// by then, so it is only logged and the file is left truncated.
+func (e *tableExport[T]) finish(err error) {
+	if err != nil {
+		logrus.Error(pii.Scrub(fmt.Sprintf("export %s: %v", e.filename, err)))
+		return
+	}
+	if !e.started() {
+		// no rows, the file has the header only
+		if err := e.start(); err != nil {
+			logrus.Error(pii.Scrub(fmt.Sprintf("export %s: %v", e.filename, err)))
+			return
+		}
+	}
+	if err := e.w.Close(); err != nil {
+		logrus.Error(pii.Scrub(fmt.Sprintf("export %s: %v", e.filename, err)))
+	}
+}
+
+// escapeFormula prefixes text that spreadsheets would evaluate as a formula with an apostrophe, so that a task
+// description such as "=HYPERLINK(...)" is shown as typed. Only CSV needs it, XLSX writes text as inline strings
+// that are never evaluated.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func cellValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(time.RFC3339)
+	case *int:
+		if v == nil {
+			return nil
+		}
+		return *v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceRoutes struct {
+	service service.Invoice
+}
+
+func newInvoiceRoutes(handler *gin.RouterGroup, service service.Invoice) {
+	r := &InvoiceRoutes{service}
+	handler.POST("", r.create)
+	handler.GET(":id", r.get)
+}
+
+type createInvoiceInput struct {
+	DateFrom  time.Time     `json:"dateFrom" binding:"required"`
+	DateTo    time.Time     `json:"dateTo" binding:"required"`
+	ProjectID *int          `json:"projectId,omitempty"`
+	ClientID  *int          `json:"clientId,omitempty"`
+	GroupBy   string        `json:"groupBy,omitempty" binding:"omitempty,oneof=task project"`
+	Rate      int64         `json:"rate,omitempty" binding:"gte=0"`                     // per hour in minor currency units
+	UserRates map[int]int64 `json:"userRates,omitempty" binding:"omitempty,dive,gte=0"` // user id -> rate
+	TaxRate   *float64      `json:"taxRate,omitempty" binding:"omitempty,gte=0,lte=100"`
+	Currency  string        `json:"currency,omitempty" binding:"omitempty,len=3,uppercase"`
+	Customer  string        `json:"customer,omitempty" binding:"max=1024"`
+	Notes     string        `json:"notes,omitempty" binding:"max=4096"`
+}
+
+// @Summary Выставление счёта
+// @Description Bills the completed billable tasks created within the period that no invoice billed yet, optionally
+// @Description of a single project or of the projects of a client, and marks them invoiced. The client, given or
+// @Description the one of the project, provides the rate, currency and customer the input leaves empty.
+// @Description Lines bill every task (groupBy=task, default) or the tasks of a project at the same rate
+// @Description (groupBy=project). Tasks have no tags, so there is no grouping by tag, the project takes its place.
+// @Description Amounts and rates are in minor currency units, rates are per hour.
+// @Description Invoices are numbered sequentially within the year.
+// @Description The invoice is returned as JSON, HTML or PDF depending on the Accept header. Admin only
+// @Tags Invoices / Счета
+// @Accept json
+// @Produce json,text/html,application/pdf
+// @Param input body createInvoiceInput true "Invoice input"
+// @Success 200 {object} model.Invoice
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 422 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/invoices [post]
+func (r *InvoiceRoutes) create(c *gin.Context) {
+	var input createInvoiceInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.DateFrom.After(input.DateTo) {
+		newErrorResponse(c, http.StatusBadRequest, "invalid date range")
+		return
+	}
+
+	invoice, err := r.service.CreateInvoice(c, service.CreateInvoiceInput{
+		DateFrom:  input.DateFrom,
+		DateTo:    input.DateTo,
+		ProjectID: input.ProjectID,
+		ClientID:  input.ClientID,
+		GroupBy:   input.GroupBy,
+		Rate:      input.Rate,
+		UserRates: input.UserRates,
+		TaxRate:   input.TaxRate,
+		Currency:  input.Currency,
+		Customer:  input.Customer,
+		Notes:     input.Notes,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrRateRequired) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAlreadyInvoiced) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrNothingToInvoice) {
+			newErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	r.render(c, invoice)
+}
+
+// @Summary Получение счёта
+// @Description Invoice as JSON, HTML or PDF depending on the Accept header. Admin only
+// @Tags Invoices / Счета
+// @Produce json,text/html,application/pdf
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} model.Invoice
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/invoices/{id} [get]
+func (r *InvoiceRoutes) get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	invoice, err := r.service.GetInvoice(c, id)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	r.render(c, invoice)
+}
+
+// render writes the invoice in the format negotiated from the Accept header, documents are rendered in memory
+// first, so that a failure can still be answered with an error status.
+func (r *InvoiceRoutes) render(c *gin.Context, invoice model.Invoice) {
+	var buf bytes.Buffer
+	var err error
+	format := c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML, mimePDF)
+	switch format {
+	case gin.MIMEHTML:
+		err = renderInvoiceHTML(&buf, invoice)
+	case mimePDF:
+		err = renderInvoicePDF(&buf, invoice)
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="invoice-%s.pdf"`, invoice.Number))
+	default:
+		c.JSON(http.StatusOK, invoice)
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if format == gin.MIMEHTML {
+		format += "; charset=utf-8"
+	}
+	c.Data(http.StatusOK, format, buf.Bytes())
+}
@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time-tracker/internal/model"
+	"time-tracker/pkg/pdf"
+)
+
+const (
+	mimePDF    = "application/pdf"
+	dateLayout = "02.01.2006"
+)
+
+// formatAmount writes minor currency units with grouped thousands, e.g. 1234567 as "12 345,67".
+func formatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	units := strconv.FormatInt(amount/100, 10)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteString(" ")
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s%s,%02d", sign, grouped.String(), amount%100)
+}
+
+func formatHours(minutes int) string {
+	return strings.Replace(strconv.FormatFloat(hours(minutes), 'f', 2, 64), ".", ",", 1)
+}
+
+func formatTaxRate(rate float64) string {
+	return strings.Replace(strconv.FormatFloat(rate, 'f', -1, 64), ".", ",", 1)
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"amount":  formatAmount,
+	"hours":   formatHours,
+	"taxRate": formatTaxRate,
+	"inc":     func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Счёт № {{.Number}}</title>
+<style>
+body { font-family: "DejaVu Sans", Arial, sans-serif; font-size: 14px; margin: 40px; color: #222; }
+h1 { font-size: 22px; }
+table { border-collapse: collapse; width: 100%; margin: 24px 0; }
+th, td { border-bottom: 1px solid #ccc; padding: 6px 8px; text-align: left; }
+th { background: #eee; }
+.number { text-align: right; white-space: nowrap; }
+.totals td { border: none; }
+</style>
+</head>
+<body>
+<h1>Счёт № {{.Number}} от {{.CreatedAt.Format "02.01.2006"}}</h1>
+{{if .Issuer}}<p>Исполнитель: {{.Issuer}}</p>{{end}}
+{{if .Customer}}<p>Заказчик: {{.Customer}}</p>{{end}}
+<p>Период: {{.PeriodFrom.Format "02.01.2006"}} — {{.PeriodTo.Format "02.01.2006"}}</p>
+<table>
+<tr><th>№</th><th>Наименование</th><th class="number">Часы</th><th class="number">Ставка</th><th class="number">Сумма</th></tr>
+{{range $i, $line := .Lines}}<tr><td>{{inc $i}}</td><td>{{$line.Description}}</td><td class="number">{{hours $line.Duration}}</td><td class="number">{{amount $line.Rate}}</td><td class="number">{{amount $line.Amount}}</td></tr>
+{{end}}<tr class="totals"><td colspan="4" class="number">Итого:</td><td class="number">{{amount .Subtotal}}</td></tr>
+<tr class="totals"><td colspan="4" class="number">Налог ({{taxRate .TaxRate}}%):</td><td class="number">{{amount .Tax}}</td></tr>
+<tr class="totals"><td colspan="4" class="number"><b>Всего к оплате, {{.Currency}}:</b></td><td class="number"><b>{{amount .Total}}</b></td></tr>
+</table>
+{{if .Notes}}<p>{{.Notes}}</p>{{end}}
+</body>
+</html>
+`))
+
+func renderInvoiceHTML(w io.Writer, invoice model.Invoice) error {
+	return invoiceTemplate.Execute(w, invoice)
+}
+
+// renderInvoicePDF lays the invoice out like the HTML one, the lines table continues on the next pages.
+func renderInvoicePDF(w io.Writer, invoice model.Invoice) error {
+	font, err := pdf.DefaultFont()
+	if err != nil {
+		return err
+	}
+	const (
+		left, right, bottom = 50.0, pdf.PageWidth - 50, 60.0
+		size, leading       = 10.0, 14.0
+		// right edges of the number columns and the description column width
+		hoursX, rateX, amountX, descriptionX, descriptionWidth = 390.0, 470.0, right, 75.0, 240.0
+	)
+	doc := pdf.NewDocument(font, "Счёт № "+invoice.Number)
+	page := doc.AddPage()
+	y := pdf.PageHeight - 70
+
+	text := func(s string) {
+		for _, line := range doc.Wrap(s, size+1, right-left) {
+			if y < bottom {
+				page = doc.AddPage()
+				y = pdf.PageHeight - 60
+			}
+			page.Text(left, y, size+1, 0, line)
+			y -= leading + 2
+		}
+	}
+	page.Text(left, y, 18, 0, fmt.Sprintf("Счёт № %s от %s", invoice.Number, invoice.CreatedAt.Format(dateLayout)))
+	y -= 32
+	if invoice.Issuer != "" {
+		text("Исполнитель: " + invoice.Issuer)
+	}
+	if invoice.Customer != "" {
+		text("Заказчик: " + invoice.Customer)
+	}
+	text(fmt.Sprintf("Период: %s — %s", invoice.PeriodFrom.Format(dateLayout), invoice.PeriodTo.Format(dateLayout)))
+	y -= 12
+
+	header := func() {
+		page.FillRect(left, y-5, right-left, leading+4, 0.92)
+		page.Text(left+4, y, size, 0, "№")
+		page.Text(descriptionX, y, size, 0, "Наименование")
+		page.TextRight(hoursX, y, size, 0, "Часы")
+		page.TextRight(rateX, y, size, 0, "Ставка")
+		page.TextRight(amountX-4, y, size, 0, "Сумма")
+		y -= leading + 6
+	}
+	header()
+	for i, line := range invoice.Lines {
+		description := doc.Wrap(line.Description, size, descriptionWidth)
+		if y-leading*float64(len(description)) < bottom {
+			page = doc.AddPage()
+			y = pdf.PageHeight - 60
+			header()
+		}
+		page.Text(left+4, y, size, 0, strconv.Itoa(i+1))
+		page.TextRight(hoursX, y, size, 0, formatHours(line.Duration))
+		page.TextRight(rateX, y, size, 0, formatAmount(line.Rate))
+		page.TextRight(amountX-4, y, size, 0, formatAmount(line.Amount))
+		for _, l := range description {
+			page.Text(descriptionX, y, size, 0, l)
+			y -= leading
+		}
+		page.Line(left, y+leading-4, right, y+leading-4, 0.5, 0.8)
+		y -= 4
+	}
+
+	if y-4*leading < bottom {
+		page = doc.AddPage()
+		y = pdf.PageHeight - 60
+	}
+	y -= 6
+	totals := []struct {
+		label  string
+		amount int64
+		size   float64
+	}{
+		{"Итого:", invoice.Subtotal, size},
+		{fmt.Sprintf("Налог (%s%%):", formatTaxRate(invoice.TaxRate)), invoice.Tax, size},
+		{fmt.Sprintf("Всего к оплате, %s:", invoice.Currency), invoice.Total, size + 2},
+	}
+	for _, total := range totals {
+		page.TextRight(rateX, y, total.size, 0, total.label)
+		page.TextRight(amountX-4, y, total.size, 0, formatAmount(total.amount))
+		y -= leading + 2
+	}
+	if invoice.Notes != "" {
+		y -= leading
+		text(invoice.Notes)
+	}
+
+	_, err = doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,27 @@
+package v1
+
+import "testing"
+
+// the groups are separated with a no-break space, so that amounts do not wrap in the rendered invoice
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount int64
+		want   string
+	}{
+		{0, "0,00"},
+		{5, "0,05"},
+		{99, "0,99"},
+		{100, "1,00"},
+		{123456, "1\u00a0234,56"},
+		{100000, "1\u00a0000,00"},
+		{99999999, "999\u00a0999,99"},
+		{123456789012, "1\u00a0234\u00a0567\u00a0890,12"},
+		{-5, "-0,05"},
+		{-123456, "-1\u00a0234,56"},
+	}
+	for _, tt := range tests {
+		if got := formatAmount(tt.amount); got != tt.want {
+			t.Errorf("formatAmount(%d) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time-tracker/internal/service"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers set by the API gateway with the identity of the caller and its comma separated permissions, they are
+// trusted only together with the token of the gateway, clients can't grant themselves permissions.
+const (
+	actorHeader        = "X-Actor"
+	permissionsHeader  = "X-Permissions"
+	gatewayTokenHeader = "X-Gateway-Token"
+)
+
+// maxActorLength is the length of md.audit_log.actor.
+const maxActorLength = 64
+
+// newActorMiddleware stores the caller identity of requests passed by the gateway in their context, the other
+// requests are anonymous without permissions.
+func newActorMiddleware(gatewayToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := service.AnonymousActor
+		var permissions []string
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(gatewayTokenHeader)), []byte(gatewayToken)) == 1 {
+			if header := c.GetHeader(actorHeader); header != "" {
+				actor = header
+			}
+			for _, p := range strings.Split(c.GetHeader(permissionsHeader), ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					permissions = append(permissions, p)
+				}
+			}
+		}
+		if utf8.RuneCountInString(actor) > maxActorLength {
+			newErrorResponse(c, http.StatusBadRequest, "actor header is too long")
+			return
+		}
+
+		ctx := service.WithActor(c.Request.Context(), actor)
+		ctx = service.WithPermissions(ctx, permissions)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"errors"
+	"strconv"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/repository/repoerr"
+
+	"github.com/gin-gonic/gin"
+)
+
+const totalCountHeader = "X-Total-Count"
+
+// pageInput is the pagination query of list endpoints: pass nextCursor of the previous page as cursor,
+// sort is e.g. "surname,-created_at", withTotal adds the X-Total-Count header.
+type pageInput struct {
+	Limit     int    `json:"limit,omitempty" form:"limit"`
+	Cursor    string `json:"cursor,omitempty" form:"cursor"`
+	Sort      string `json:"sort,omitempty" form:"sort"`
+	WithTotal bool   `json:"withTotal,omitempty" form:"withTotal"`
+}
+
+func (p pageInput) pageRequest() pgdb.PageRequest {
+	return pgdb.PageRequest{
+		Limit:     p.Limit,
+		Cursor:    p.Cursor,
+		Sort:      p.Sort,
+		WithTotal: p.WithTotal,
+	}
+}
+
+type pageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func newPageResponse[T any](c *gin.Context, page pgdb.Page[T], withTotal bool) pageResponse[T] {
+	if withTotal {
+		c.Header(totalCountHeader, strconv.Itoa(page.Total))
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return pageResponse[T]{
+		Items:      page.Items,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func isPageError(err error) bool {
+	return errors.Is(err, repoerr.ErrInvalidCursor) || errors.Is(err, repoerr.ErrInvalidSort)
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectRoutes struct {
+	service service.Project
+}
+
+func newProjectRoutes(handler *gin.RouterGroup, service service.Project) {
+	r := &ProjectRoutes{service}
+	handler.POST("", r.create)
+	handler.GET("", r.getList)
+	handler.PUT(":id/client", r.setClient)
+}
+
+type createProjectInput struct {
+	Name     string `json:"name" binding:"required,max=256"`
+	ClientID *int   `json:"clientId,omitempty"`
+}
+
+type createProjectResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Создание проекта
+// @Description Create Project, names are unique in any case. The project belongs to the client clientId, if given
+// @Tags Projects / Проекты
+// @Accept json
+// @Produce json
+// @Param input body createProjectInput true "Project input"
+// @Success 200 {object} createProjectResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects [post]
+func (r *ProjectRoutes) create(c *gin.Context) {
+	var input createProjectInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := r.service.CreateProject(c, input.Name, input.ClientID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, repoerr.ErrAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, createProjectResponse{ID: id})
+}
+
+// @Summary Получение списка проектов
+// @Description Project list ordered by name
+// @Tags Projects / Проекты
+// @Produce json
+// @Success 200 {array} model.Project
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects [get]
+func (r *ProjectRoutes) getList(c *gin.Context) {
+	items, err := r.service.ListProjects(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+type setProjectClientInput struct {
+	ClientID *int `json:"clientId"` // null leaves the project without a client
+}
+
+type setProjectClientResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Смена клиента проекта
+// @Description Moves the project to the client clientId, null leaves the project without a client
+// @Tags Projects / Проекты
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param input body setProjectClientInput true "Client"
+// @Success 200 {object} setProjectClientResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/{id}/client [put]
+func (r *ProjectRoutes) setClient(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input setProjectClientInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = r.service.SetProjectClient(c, id, input.ClientID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, setProjectClientResponse{Success: true})
+}
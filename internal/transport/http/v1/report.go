@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+	"time-tracker/internal/model"
+	"time-tracker/internal/repository/pgdb"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportRoutes struct {
+	service service.Report
+}
+
+func newReportRoutes(handler *gin.RouterGroup, service service.Report) {
+	r := &ReportRoutes{service}
+	handler.GET("worklog", r.worklog)
+	handler.GET("clients", r.clients)
+}
+
+type worklogInput struct {
+	DateFrom time.Time `json:"dateFrom" time_format:"2006-01-02T15:04:05Z07:00" form:"dateFrom" binding:"required"`
+	DateTo   time.Time `json:"dateTo" time_format:"2006-01-02T15:04:05Z07:00" form:"dateTo" binding:"required"`
+	UserID   int       `json:"userId,omitempty" form:"userId"`
+	exportInput
+}
+
+// worklogExportColumns are the spreadsheet columns of the worklog report, durations are in hours.
+var worklogExportColumns = []exportColumn[model.WorklogEntry]{
+	{name: "user_id", value: func(e model.WorklogEntry) any { return e.UserID }},
+	{name: "name", value: func(e model.WorklogEntry) any { return e.Name }},
+	{name: "surname", value: func(e model.WorklogEntry) any { return e.Surname }},
+	{name: "patronymic", value: func(e model.WorklogEntry) any { return e.Patronymic }},
+	{name: "status", value: func(e model.WorklogEntry) any { return e.Status }},
+	{name: "tasks", value: func(e model.WorklogEntry) any { return e.Tasks }},
+	{name: "duration_hours", value: func(e model.WorklogEntry) any { return hours(e.Duration) }},
+}
+
+// @Summary Отчёт по трудозатратам
+// @Description Tracked time per user in the period, users of any status are included.
+// @Description With Accept text/csv or XLSX the report is exported,
+// @Description columns: user_id, name, surname, patronymic, status, tasks, duration_hours
+// @Tags Reports / Отчёты
+// @Accept json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param input query worklogInput true "Filter"
+// @Success 200 {array} model.WorklogEntry
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/reports/worklog [get]
+func (r *ReportRoutes) worklog(c *gin.Context) {
+	var input worklogInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.DateFrom.After(input.DateTo) {
+		newErrorResponse(c, http.StatusBadRequest, "invalid date range")
+		return
+	}
+
+	filter := pgdb.WorklogFilter{
+		DateFrom: input.DateFrom,
+		DateTo:   input.DateTo,
+		UserID:   input.UserID,
+	}
+	if format := exportFormat(c); format != "" {
+		columns, err := selectColumns(worklogExportColumns, input.Columns)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		export := newTableExport(c, format, "worklog", columns)
+		err = r.service.Worklog(c, filter, export.write)
+		if err != nil && !export.started() {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		export.finish(err)
+		return
+	}
+
+	entries := []model.WorklogEntry{}
+	err := r.service.Worklog(c, filter, func(e model.WorklogEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// clientWorklogExportColumns are the spreadsheet columns of the client report, durations are in hours.
+var clientWorklogExportColumns = []exportColumn[model.ClientWorklogEntry]{
+	{name: "client_id", value: func(e model.ClientWorklogEntry) any { return e.ClientID }},
+	{name: "name", value: func(e model.ClientWorklogEntry) any { return e.Name }},
+	{name: "projects", value: func(e model.ClientWorklogEntry) any { return e.Projects }},
+	{name: "tasks", value: func(e model.ClientWorklogEntry) any { return e.Tasks }},
+	{name: "duration_hours", value: func(e model.ClientWorklogEntry) any { return hours(e.Duration) }},
+	{name: "billable_hours", value: func(e model.ClientWorklogEntry) any { return hours(e.BillableDuration) }},
+}
+
+// @Summary Отчёт по клиентам
+// @Description Tracked time per client in the period, the time of tasks without a client has a null client_id
+// @Description and comes last. With Accept text/csv or XLSX the report is exported,
+// @Description columns: client_id, name, projects, tasks, duration_hours, billable_hours
+// @Tags Reports / Отчёты
+// @Accept json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param input query worklogInput true "Filter"
+// @Success 200 {array} model.ClientWorklogEntry
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/reports/clients [get]
+func (r *ReportRoutes) clients(c *gin.Context) {
+	var input worklogInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.DateFrom.After(input.DateTo) {
+		newErrorResponse(c, http.StatusBadRequest, "invalid date range")
+		return
+	}
+
+	filter := pgdb.WorklogFilter{
+		DateFrom: input.DateFrom,
+		DateTo:   input.DateTo,
+		UserID:   input.UserID,
+	}
+	if format := exportFormat(c); format != "" {
+		columns, err := selectColumns(clientWorklogExportColumns, input.Columns)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		export := newTableExport(c, format, "clients", columns)
+		err = r.service.ClientWorklog(c, filter, export.write)
+		if err != nil && !export.started() {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		export.finish(err)
+		return
+	}
+
+	entries := []model.ClientWorklogEntry{}
+	err := r.service.ClientWorklog(c, filter, func(e model.ClientWorklogEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time-tracker/internal/service"
+	"time-tracker/pkg/pii"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// piiFields are JSON fields whose values are masked for callers without the pii:read permission,
+// nested values are masked too so audit diffs of these fields are covered.
+var piiFields = map[string]bool{
+	"passport_number": true,
+	"address":         true,
+	"email":           true,
+}
+
+// newResponse writes obj as JSON with personal data masked unless the caller may read it.
+func newResponse(c *gin.Context, statusCode int, obj any) {
+	if service.HasPermission(c, service.PermissionPIIRead) {
+		c.JSON(statusCode, obj)
+		return
+	}
+
+	masked, err := maskResponse(obj)
+	if err != nil {
+		logrus.Error(pii.Scrub(err.Error()))
+		newErrorResponse(c, http.StatusInternalServerError, "could not build response")
+		return
+	}
+	c.JSON(statusCode, masked)
+}
+
+func maskResponse(obj any) (any, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return maskValue(value, false), nil
+}
+
+func maskValue(value any, sensitive bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = maskValue(item, sensitive || piiFields[key])
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = maskValue(item, sensitive)
+		}
+		return v
+	case string:
+		if sensitive && v != service.AuditRedacted {
+			return pii.Mask(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
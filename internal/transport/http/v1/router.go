@@ -10,7 +10,9 @@ import (
 	_ "time-tracker/docs"
 )
 
-func NewRouter(handler *gin.Engine, services *service.Services) {
+func NewRouter(handler *gin.Engine, services *service.Services, gatewayToken string) {
+	// let services read values stored in the request context through *gin.Context
+	handler.ContextWithFallback = true
 	handler.Use(gin.Recovery())
 
 	handler.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -21,9 +23,18 @@ func NewRouter(handler *gin.Engine, services *service.Services) {
 		})
 	})
 
-	v1 := handler.Group("/api/v1")
+	v1 := handler.Group("/api/v1", newActorMiddleware(gatewayToken))
 	{
 		newUserRoutes(v1.Group("/users"), services.User)
 		newTaskRoutes(v1.Group("/tasks"), services.Task)
+		newTaskImportRoutes(v1.Group("/tasks"), services.TrackerImport)
+		newProjectRoutes(v1.Group("/projects"), services.Project)
+		newBudgetRoutes(v1.Group("/projects"), services.Budget)
+		newClientRoutes(v1.Group("/clients"), services.Client)
+		newInvoiceRoutes(v1.Group("/invoices"), services.Invoice)
+		newWebhookRoutes(v1.Group("/webhooks"), services.Webhook)
+		newAuditRoutes(v1.Group("/audit"), services.Audit)
+		newReportRoutes(v1.Group("/reports"), services.Report)
+		newCalendarRoutes(v1.Group("/users"), services.Calendar)
 	}
-}
\ No newline at end of file
+}
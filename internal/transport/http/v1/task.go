@@ -2,9 +2,11 @@ package v1
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
+	"time-tracker/internal/model"
 	"time-tracker/internal/repository/pgdb"
 	"time-tracker/internal/repository/repoerr"
 	"time-tracker/internal/service"
@@ -22,22 +24,48 @@ func newTaskRoutes(handler *gin.RouterGroup, service service.Task) {
 	handler.GET("", r.getList)
 	handler.GET(":id", r.get)
 	handler.POST(":id/complete", r.complete)
+	handler.DELETE(":id", r.delete)
+	handler.POST(":id/restore", r.restore)
 }
 
 type getTaskListInput struct {
 	UserID   int       `json:"userId" form:"userId" binding:"required"`
 	DateTo   time.Time `json:"dateTo" time_format:"2006-01-02T15:04:05Z07:00" form:"dateTo" binding:"required"`
 	DateFrom time.Time `json:"dateFrom" time_format:"2006-01-02T15:04:05Z07:00" form:"dateFrom" binding:"required"`
+
+	IncludeDeleted bool `json:"includeDeleted,omitempty" form:"includeDeleted"`
+	pageInput
+	exportInput
+}
+
+// taskExportColumns are the spreadsheet columns of task lists, durations are in hours.
+var taskExportColumns = []exportColumn[model.Task]{
+	{name: "id", value: func(t model.Task) any { return t.ID }},
+	{name: "user_id", value: func(t model.Task) any { return t.UserID }},
+	{name: "project_id", value: func(t model.Task) any { return t.ProjectID }},
+	{name: "description", value: func(t model.Task) any { return t.Description }},
+	{name: "duration_hours", value: func(t model.Task) any { return hours(t.Duration) }},
+	{name: "completed", value: func(t model.Task) any { return t.Completed }},
+	{name: "billable", value: func(t model.Task) any { return t.Billable }},
+	{name: "invoice_id", value: func(t model.Task) any { return t.InvoiceID }},
+	{name: "created_at", value: func(t model.Task) any { return t.CreatedAt }},
+	{name: "updated_at", value: func(t model.Task) any { return t.UpdatedAt }},
+	{name: "deleted_at", value: func(t model.Task) any { return t.DeletedAt }},
 }
 
 // @Summary Получение списка элементов "Задача"
-// @Description Task list
+// @Description Task list, sortable by id, description, duration, created_at (longest first by default).
+// @Description With Accept text/csv or XLSX the whole list is exported ignoring limit and cursor,
+// @Description columns: id, user_id, project_id, description, duration_hours, completed, billable, invoice_id,
+// @Description created_at, updated_at, deleted_at
 // @Tags Tasks / Задачи
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param input query getTaskListInput true "Filter"
-// @Success 200 {array} model.Task
+// @Success 200 {object} pageResponse[model.Task]
+// @Header 200 {integer} X-Total-Count "Number of matching tasks, only with withTotal"
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/tasks [get]
@@ -53,15 +81,63 @@ func (r *TaskRoutes) getList(c *gin.Context) {
 		return
 	}
 
-	items, err := r.service.ListTasks(c, input.UserID, pgdb.ListTasksFilter{
-		DateFrom: input.DateFrom,
-		DateTo:   input.DateTo,
-	})
+	filter := pgdb.ListTasksFilter{
+		DateFrom:       input.DateFrom,
+		DateTo:         input.DateTo,
+		IncludeDeleted: input.IncludeDeleted,
+		PageRequest:    input.pageRequest(),
+	}
+	if format := exportFormat(c); format != "" {
+		r.exportList(c, format, input, filter)
+		return
+	}
+
+	items, err := r.service.ListTasks(c, input.UserID, filter)
 	if err != nil {
+		if isPageError(err) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, items)
+	c.JSON(http.StatusOK, newPageResponse(c, items, input.WithTotal))
+}
+
+func (r *TaskRoutes) exportList(c *gin.Context, format string, input getTaskListInput, filter pgdb.ListTasksFilter) {
+	columns, err := selectColumns(taskExportColumns, input.Columns)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export := newTableExport(c, format, fmt.Sprintf("tasks-%d", input.UserID), columns)
+	err = r.service.ExportTasks(c, input.UserID, filter, export.write)
+	if err != nil && !export.started() {
+		if isPageError(err) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	export.finish(err)
 }
 
 // @Summary Получение элемента "Задача"
@@ -70,8 +146,10 @@ func (r *TaskRoutes) getList(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID"
+// @Param includeDeleted query bool false "Include soft deleted task (admin only)"
 // @Success 200 {object} model.Task
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/tasks/{id} [get]
@@ -81,8 +159,21 @@ func (r *TaskRoutes) get(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
 		return
 	}
-	item, err := r.service.GetTask(c, id)
+	var input includeDeletedInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	item, err := r.service.GetTask(c, id, input.IncludeDeleted)
 	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -91,7 +182,9 @@ func (r *TaskRoutes) get(c *gin.Context) {
 
 type createTaskInput struct {
 	UserID      int    `json:"userId" form:"userId" binding:"required"`
+	ProjectID   *int   `json:"projectId,omitempty" form:"projectId"`
 	Description string `json:"description" form:"description" binding:"required"`
+	Billable    *bool  `json:"billable,omitempty" form:"billable"` // true by default
 }
 
 type createTaskResponse struct {
@@ -107,6 +200,7 @@ type createTaskResponse struct {
 // @Success 200 {object} createTaskResponse
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/tasks [post]
 func (r *TaskRoutes) create(c *gin.Context) {
@@ -117,9 +211,19 @@ func (r *TaskRoutes) create(c *gin.Context) {
 	}
 	id, err := r.service.CreateTask(c, pgdb.CreateTaskInput{
 		UserID:      input.UserID,
+		ProjectID:   input.ProjectID,
 		Description: input.Description,
+		Billable:    input.Billable == nil || *input.Billable,
 	})
 	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserInactive) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -168,3 +272,83 @@ func (r *TaskRoutes) complete(c *gin.Context) {
 		Success: true,
 	})
 }
+
+type deleteTaskResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Удаление задачи
+// @Description Soft delete specified task, invoiced tasks can't be deleted
+// @Tags Tasks / Задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} deleteTaskResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tasks/{id} [delete]
+func (r *TaskRoutes) delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	err = r.service.DeleteTask(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTaskInvoiced) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, deleteTaskResponse{
+		Success: true,
+	})
+}
+
+type restoreTaskResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Восстановление задачи
+// @Description Restore soft deleted task (admin only)
+// @Tags Tasks / Задачи
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} restoreTaskResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tasks/{id}/restore [post]
+func (r *TaskRoutes) restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	err = r.service.RestoreTask(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, restoreTaskResponse{
+		Success: true,
+	})
+}
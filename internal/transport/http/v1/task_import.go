@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time-tracker/internal/service"
+	"time-tracker/pkg/tracker"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskImportRoutes struct {
+	service service.TrackerImport
+}
+
+func newTaskImportRoutes(handler *gin.RouterGroup, service service.TrackerImport) {
+	r := &TaskImportRoutes{service}
+	handler.POST("import", r.importTasks)
+}
+
+type importTasksInput struct {
+	Format   string `json:"format,omitempty" form:"format" binding:"omitempty,oneof=toggl clockify harvest"`
+	Timezone string `json:"timezone,omitempty" form:"timezone"`
+	DryRun   bool   `json:"dryRun,omitempty" form:"dryRun"`
+}
+
+type importTaskResult struct {
+	Line       int    `json:"line"`
+	Status     string `json:"status"`
+	ExternalID string `json:"externalId,omitempty"`
+	UserID     int    `json:"userId,omitempty"`
+	ProjectID  int    `json:"projectId,omitempty"`
+	TaskID     int    `json:"taskId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type importTasksResponse struct {
+	DryRun   bool               `json:"dryRun"`
+	Format   string             `json:"format"`
+	Summary  map[string]int     `json:"summary"`  // status -> rows
+	Projects []string           `json:"projects"` // created, in the dry run to be created
+	Results  []importTaskResult `json:"results"`
+	Warnings []string           `json:"warnings,omitempty"` // failed budget checks, the tasks are imported
+}
+
+// @Summary Импорт трудозатрат из других трекеров
+// @Description Imports the detailed CSV export of Toggl Track, Clockify or Harvest as completed tasks, the format
+// @Description is detected from the header unless given. Users are matched by email, then by full name in either
+// @Description order, projects are matched by name and created when missing. Entries are identified by a hash
+// @Description of their fields, so importing a file again skips the entries imported before.
+// @Description Statuses: created, valid (dry run), exists, duplicate, unknown_user, ambiguous_user, invalid, failed
+// @Description The body is limited to TASK_IMPORT_MAX_ROWS entries and 32 MiB, a larger one is rejected with 413
+// @Tags Tasks / Задачи
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "Export format" Enums(toggl, clockify, harvest)
+// @Param timezone query string false "Time zone of the exported times, TASK_IMPORT_TIMEZONE by default"
+// @Param dryRun query bool false "Match rows without creating projects and tasks"
+// @Success 200 {object} importTasksResponse
+// @Failure 400 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tasks/import [post]
+func (r *TaskImportRoutes) importTasks(c *gin.Context) {
+	var input importTasksInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if c.ContentType() != mimeCSV {
+		newErrorResponse(c, http.StatusUnsupportedMediaType, "expected "+mimeCSV)
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	report, err := r.service.ImportTimeEntries(c, body, service.TrackerImportInput{
+		Format:   input.Format,
+		Timezone: input.Timezone,
+		DryRun:   input.DryRun,
+	})
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, service.ErrImportTooLarge) || errors.As(err, &maxBytesErr) {
+			newErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		if errors.Is(err, tracker.ErrInvalidFile) || errors.Is(err, service.ErrInvalidTimezone) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := importTasksResponse{
+		DryRun:   input.DryRun,
+		Format:   report.Format,
+		Summary:  make(map[string]int),
+		Projects: make([]string, 0, len(report.Projects)),
+		Results:  make([]importTaskResult, 0, len(report.Results)),
+		Warnings: report.Warnings,
+	}
+	response.Projects = append(response.Projects, report.Projects...)
+	for _, result := range report.Results {
+		response.Summary[result.Status]++
+		response.Results = append(response.Results, importTaskResult(result))
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -1,15 +1,24 @@
 package v1
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/mail"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"time-tracker/internal/model"
 	"time-tracker/internal/repository/pgdb"
 	"time-tracker/internal/repository/repoerr"
 	"time-tracker/internal/service"
+	"time-tracker/pkg/document"
+	"time-tracker/pkg/peopleinfo"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,17 +30,36 @@ type UserRoutes struct {
 func newUserRoutes(handler *gin.RouterGroup, service service.User) {
 	r := &UserRoutes{service}
 	handler.POST("", r.create)
+	handler.POST("import", r.importUsers)
 	handler.GET("", r.getList)
 	handler.GET(":id", r.get)
 	handler.PATCH(":id", r.update)
 	handler.DELETE(":id", r.delete)
+	handler.POST(":id/restore", r.restore)
+	handler.GET(":id/export", r.export)
+	handler.POST(":id/anonymize", r.anonymize)
+	handler.GET(":id/duplicates", r.duplicates)
+	handler.POST(":id/merge", r.merge)
+	handler.POST(":id/status", r.changeStatus)
+	handler.GET(":id/status", r.statusHistory)
 }
 
+// createUserInput identifies the user by a document, a russian passport unless documentType says otherwise.
+// The profile is looked up by the document when name and surname are not given, documents without
+// a lookup require them.
 type createUserInput struct {
-	PassportNumber string `json:"passportNumber" binding:"required"`
+	PassportNumber  string `json:"passportNumber" binding:"required"` // number of the document
+	DocumentType    string `json:"documentType,omitempty" enums:"ru_passport,foreign_passport,residence_permit"`
+	DocumentCountry string `json:"documentCountry,omitempty"` // ISO 3166-1 alpha-2, defaults to the country of the document type
+	Name            string `json:"name,omitempty"`
+	Surname         string `json:"surname,omitempty"`
+	Patronymic      string `json:"patronymic,omitempty"`
+	Address         string `json:"address,omitempty"`
+	Email           string `json:"email,omitempty"`
 }
 type createUserResponse struct {
-	ID int `json:"id"`
+	ID               int    `json:"id"`
+	EnrichmentStatus string `json:"enrichmentStatus"`
 }
 
 // @Summary Создание элемента "Пользователь"
@@ -41,9 +69,13 @@ type createUserResponse struct {
 // @Produce json
 // @Param input body createUserInput true "User input"
 // @Success 200 {object} createUserResponse
+// @Success 202 {object} createUserResponse "People info API is unavailable, the user is enriched in background"
 // @Failure 400 {object} errorResponse
 // @Failure 409 {object} errorResponse
+// @Failure 422 {object} errorResponse
 // @Failure 500 {object} errorResponse
+// @Failure 502 {object} errorResponse
+// @Failure 503 {object} errorResponse
 // @Router /api/v1/users [post]
 func (r *UserRoutes) create(c *gin.Context) {
 	var input createUserInput
@@ -52,34 +84,76 @@ func (r *UserRoutes) create(c *gin.Context) {
 		return
 	}
 
-	re, _ := regexp.Compile(`^\d{4} \d{6}$`)
-	if !re.MatchString(input.PassportNumber) {
-		newErrorResponse(c, http.StatusBadRequest, "invalid passport number")
+	if msg, ok := validateUpdateUser(updateUserInput{
+		Name:       &input.Name,
+		Surname:    &input.Surname,
+		Patronymic: &input.Patronymic,
+		Address:    &input.Address,
+		Email:      &input.Email,
+	}); !ok {
+		newErrorResponse(c, http.StatusBadRequest, msg)
 		return
 	}
 
-	id, err := r.service.CreateUser(c, input.PassportNumber)
+	user, err := r.service.CreateUser(c, service.CreateUserInput{
+		DocumentType:    input.DocumentType,
+		DocumentCountry: input.DocumentCountry,
+		PassportNumber:  input.PassportNumber,
+		Name:            input.Name,
+		Surname:         input.Surname,
+		Patronymic:      input.Patronymic,
+		Address:         input.Address,
+		Email:           input.Email,
+	})
 	if err != nil {
+		if errors.Is(err, document.ErrInvalid) || errors.Is(err, service.ErrProfileRequired) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, repoerr.ErrAlreadyExists) {
 			newErrorResponse(c, http.StatusConflict, err.Error())
 			return
 		}
+		if errors.Is(err, peopleinfo.ErrUnavailable) {
+			newErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		if errors.Is(err, peopleinfo.ErrBadResponse) {
+			newErrorResponse(c, http.StatusBadGateway, err.Error())
+			return
+		}
+		if errors.Is(err, peopleinfo.ErrNotFound) {
+			newErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, createUserResponse{
-		ID: id,
+	statusCode := http.StatusOK
+	if user.EnrichmentStatus == model.EnrichmentStatusPending {
+		statusCode = http.StatusAccepted
+	}
+	c.JSON(statusCode, createUserResponse{
+		ID:               user.ID,
+		EnrichmentStatus: user.EnrichmentStatus,
 	})
 }
 
+// includeDeletedInput is the query of single item handlers, soft deleted items are shown to admins only.
+type includeDeletedInput struct {
+	IncludeDeleted bool `json:"includeDeleted,omitempty" form:"includeDeleted"`
+}
+
 // @Summary Получение элемента "Пользователь"
-// @Description Get User
+// @Description Get User. Passport number and address are masked without the pii:read permission
 // @Tags Users / Пользователи
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param includeDeleted query bool false "Include soft deleted user (admin only)"
 // @Success 200 {object} model.User
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/users/{id} [get]
@@ -89,14 +163,27 @@ func (r *UserRoutes) get(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
 		return
 	}
+	var input includeDeletedInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	item, err := r.service.GetUser(c, id)
+	item, err := r.service.GetUser(c, id, input.IncludeDeleted)
 	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	newResponse(c, http.StatusOK, item)
 }
 
 type getUserListInput struct {
@@ -104,19 +191,53 @@ type getUserListInput struct {
 	Surname        string `json:"surname,omitempty" form:"surname"`
 	Patronymic     string `json:"patronymic,omitempty" form:"patronymic"`
 	PassportNumber string `json:"passportNumber,omitempty" form:"passportNumber"`
-	Address        string `json:"address,omitempty" form:"address"`
-	Offset         int    `json:"offset,omitempty" form:"offset"`
-	Limit          int    `json:"limit,omitempty" form:"limit"`
+	// DocumentType and DocumentCountry filter by the document, passportNumber is matched within them
+	DocumentType    string `json:"documentType,omitempty" form:"documentType"`
+	DocumentCountry string `json:"documentCountry,omitempty" form:"documentCountry"`
+	Address         string `json:"address,omitempty" form:"address"`
+	// Query is a typo tolerant search across name, surname, patronymic and address, results are ordered by relevance
+	Query string `json:"q,omitempty" form:"q"`
+	// Status is a comma separated list of lifecycle statuses, active and suspended users by default
+	Status         string `json:"status,omitempty" form:"status"`
+	IncludeDeleted bool   `json:"includeDeleted,omitempty" form:"includeDeleted"`
+	pageInput
+	exportInput
+}
+
+// userExportColumns are the spreadsheet columns of user lists.
+var userExportColumns = []exportColumn[model.User]{
+	{name: "id", value: func(u model.User) any { return u.ID }},
+	{name: "name", value: func(u model.User) any { return u.Name }},
+	{name: "surname", value: func(u model.User) any { return u.Surname }},
+	{name: "patronymic", value: func(u model.User) any { return u.Patronymic }},
+	{name: "document_type", value: func(u model.User) any { return u.DocumentType }},
+	{name: "document_country", value: func(u model.User) any { return u.DocumentCountry }},
+	{name: "passport_number", value: func(u model.User) any { return u.PassportNumber }, pii: true},
+	{name: "address", value: func(u model.User) any { return u.Address }, pii: true},
+	{name: "email", value: func(u model.User) any { return u.Email }, pii: true},
+	{name: "status", value: func(u model.User) any { return u.Status }},
+	{name: "enrichment_status", value: func(u model.User) any { return u.EnrichmentStatus }},
+	{name: "created_at", value: func(u model.User) any { return u.CreatedAt }},
+	{name: "updated_at", value: func(u model.User) any { return u.UpdatedAt }},
+	{name: "deleted_at", value: func(u model.User) any { return u.DeletedAt }},
 }
 
 // @Summary Получение списка элементов "Пользователь"
-// @Description User list
+// @Description User list. Name filters match both Cyrillic and Latin spellings ("Ivanov" finds "Иванов").
+// @Description Sortable by id, name, surname, patronymic, created_at, results of q are ordered by relevance.
+// @Description Passport numbers and addresses are masked without the pii:read permission.
+// @Description Terminated users are listed only when requested by status, e.g. status=active,suspended,terminated.
+// @Description With Accept text/csv or XLSX the whole list is exported ignoring limit and cursor, columns: id, name,
+// @Description surname, patronymic, document_type, document_country, passport_number, address, email, status,
+// @Description enrichment_status, created_at, updated_at, deleted_at
 // @Tags Users / Пользователи
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param input query getUserListInput true "Filter"
-// @Success 200 {array} model.User
+// @Success 200 {object} pageResponse[model.User]
+// @Header 200 {integer} X-Total-Count "Number of matching users, only with withTotal"
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/users [get]
@@ -132,57 +253,137 @@ func (r *UserRoutes) getList(c *gin.Context) {
 		return
 	}
 
-	items, err := r.service.ListUsers(c, service.ListUsersFilter{
-		Name:           input.Name,
-		Surname:        input.Surname,
-		Patronymic:     input.Patronymic,
-		PassportNumber: input.PassportNumber,
-		Address:        input.Address,
-		Offset:         input.Offset,
-		Limit:          input.Limit,
-	})
+	filter := pgdb.ListUsersFilter{
+		Name:            input.Name,
+		Surname:         input.Surname,
+		Patronymic:      input.Patronymic,
+		PassportNumber:  input.PassportNumber,
+		DocumentType:    input.DocumentType,
+		DocumentCountry: input.DocumentCountry,
+		Address:         input.Address,
+		Query:           input.Query,
+		Statuses:        userStatuses(input.Status),
+		IncludeDeleted:  input.IncludeDeleted,
+		PageRequest:     input.pageRequest(),
+	}
+	if format := exportFormat(c); format != "" {
+		r.exportList(c, format, input, filter)
+		return
+	}
 
+	items, err := r.service.ListUsers(c, filter)
 	if err != nil {
+		if isPageError(err) || errors.Is(err, document.ErrInvalid) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	if len(items) == 0 {
-		items = []model.User{}
+	newResponse(c, http.StatusOK, newPageResponse(c, items, input.WithTotal))
+}
+
+func (r *UserRoutes) exportList(c *gin.Context, format string, input getUserListInput, filter pgdb.ListUsersFilter) {
+	columns, err := selectColumns(userExportColumns, input.Columns)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export := newTableExport(c, format, "users", columns)
+	err = r.service.ExportUsers(c, filter, export.write)
+	if err != nil && !export.started() {
+		if isPageError(err) || errors.Is(err, document.ErrInvalid) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
 	}
-	c.JSON(http.StatusOK, items)
+	export.finish(err)
+}
+
+// namePattern accepts names in any script, parts may be joined by hyphens or apostrophes: "Анна-Мария", "O'Brien".
+var namePattern = regexp.MustCompile(`^\p{L}+(?:[-'’]\p{L}+)*$`)
+
+const maxQueryWords = 8
+
+func validName(name string) bool {
+	return namePattern.MatchString(name) && utf8.RuneCountInString(name) <= 36
+}
+
+// validEmail accepts a bare address such as "ivanov@example.com", display names are rejected.
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email && len(email) <= 254
 }
 
 func validateUser(input getUserListInput) (string, bool) {
 	var errs []string
-	pattern := regexp.MustCompile(`^[a-zA-Z]+$`)
-	if input.Name != "" && (!pattern.MatchString(input.Name) || len(input.Name) > 36) {
+	if input.Name != "" && !validName(input.Name) {
 		errs = append(errs, "name is invalid")
 	}
-	if input.Surname != "" && (!pattern.MatchString(input.Surname) || len(input.Surname) > 36) {
+	if input.Surname != "" && !validName(input.Surname) {
 		errs = append(errs, "surname is invalid")
 	}
-	if input.Patronymic != "" && (!pattern.MatchString(input.Patronymic) || len(input.Patronymic) > 36) {
+	if input.Patronymic != "" && !validName(input.Patronymic) {
 		errs = append(errs, "patronymic is invalid")
 	}
-	if input.Address != "" && len(input.Address) > 256 {
+	if input.Address != "" && utf8.RuneCountInString(input.Address) > 256 {
 		errs = append(errs, "address too long")
 	}
+	if input.Query != "" && (utf8.RuneCountInString(input.Query) > 256 || len(strings.Fields(input.Query)) > maxQueryWords) {
+		errs = append(errs, "q is too long")
+	}
 
-	re, _ := regexp.Compile(`^\d{4} \d{6}$`)
-	if !re.MatchString(input.PassportNumber) {
-		errs = append(errs, "passport number is invalid")
+	for _, status := range userStatuses(input.Status) {
+		if !validUserStatus(status) {
+			errs = append(errs, "status is invalid")
+			break
+		}
 	}
 
 	msg := strings.Join(errs, ", ")
 	return msg, len(errs) == 0
 }
 
+func userStatuses(value string) []string {
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func validUserStatus(status string) bool {
+	switch status {
+	case model.UserStatusActive, model.UserStatusSuspended, model.UserStatusTerminated:
+		return true
+	}
+	return false
+}
+
 type updateUserInput struct {
-	Name           *string `json:"name,omitempty"`
-	Surname        *string `json:"surname,omitempty"`
-	Patronymic     *string `json:"patronymic,omitempty"`
-	PassportNumber *string `json:"passportNumber,omitempty"`
-	Address        *string `json:"address,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	Surname         *string `json:"surname,omitempty"`
+	Patronymic      *string `json:"patronymic,omitempty"`
+	PassportNumber  *string `json:"passportNumber,omitempty"`
+	DocumentType    *string `json:"documentType,omitempty" enums:"ru_passport,foreign_passport,residence_permit"`
+	DocumentCountry *string `json:"documentCountry,omitempty"`
+	Address         *string `json:"address,omitempty"`
+	Email           *string `json:"email,omitempty"`
+	// ProfilePinned keeps manual corrections from being overwritten by the People info API sync
+	ProfilePinned *bool `json:"profilePinned,omitempty"`
 }
 type updateUserResponse struct {
 	Success bool `json:"success"`
@@ -217,17 +418,29 @@ func (r *UserRoutes) update(c *gin.Context) {
 		return
 	}
 	err = r.service.UpdateUser(c, id, pgdb.UpdateUserInput{
-		Name:           input.Name,
-		Surname:        input.Surname,
-		Patronymic:     input.Patronymic,
-		PassportNumber: input.PassportNumber,
-		Address:        input.Address,
+		Name:            input.Name,
+		Surname:         input.Surname,
+		Patronymic:      input.Patronymic,
+		PassportNumber:  input.PassportNumber,
+		DocumentType:    input.DocumentType,
+		DocumentCountry: input.DocumentCountry,
+		Address:         input.Address,
+		Email:           input.Email,
+		ProfilePinned:   input.ProfilePinned,
 	})
 	if err != nil {
+		if errors.Is(err, document.ErrInvalid) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, repoerr.ErrNotFound) {
 			newErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
+		if errors.Is(err, repoerr.ErrAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -238,23 +451,20 @@ func (r *UserRoutes) update(c *gin.Context) {
 
 func validateUpdateUser(input updateUserInput) (string, bool) {
 	var errs []string
-	pattern := regexp.MustCompile(`^[a-zA-Z]+$`)
-	if input.Name != nil && (*input.Name != "" && (!pattern.MatchString(*input.Name) || len(*input.Name) > 36)) {
+	if input.Name != nil && (*input.Name != "" && !validName(*input.Name)) {
 		errs = append(errs, "name is invalid")
 	}
-	if input.Surname != nil && (*input.Surname != "" && (!pattern.MatchString(*input.Surname) || len(*input.Surname) > 36)) {
+	if input.Surname != nil && (*input.Surname != "" && !validName(*input.Surname)) {
 		errs = append(errs, "surname is invalid")
 	}
-	if input.Patronymic != nil && (*input.Patronymic != "" && (!pattern.MatchString(*input.Patronymic) || len(*input.Patronymic) > 36)) {
+	if input.Patronymic != nil && (*input.Patronymic != "" && !validName(*input.Patronymic)) {
 		errs = append(errs, "patronymic is invalid")
 	}
-	if input.Address != nil && (*input.Address != "" && len(*input.Address) > 256) {
+	if input.Address != nil && (*input.Address != "" && utf8.RuneCountInString(*input.Address) > 256) {
 		errs = append(errs, "address too long")
 	}
-
-	re, _ := regexp.Compile(`^\d{4} \d{6}$`)
-	if input.PassportNumber != nil && !re.MatchString(*input.PassportNumber) {
-		errs = append(errs, "passport number is invalid")
+	if input.Email != nil && (*input.Email != "" && !validEmail(*input.Email)) {
+		errs = append(errs, "email is invalid")
 	}
 
 	msg := strings.Join(errs, ", ")
@@ -285,6 +495,10 @@ func (r *UserRoutes) delete(c *gin.Context) {
 
 	err = r.service.DeleteUser(c, id)
 	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -292,3 +506,351 @@ func (r *UserRoutes) delete(c *gin.Context) {
 		Success: true,
 	})
 }
+
+type restoreUserResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Restore user
+// @Description Restore soft deleted user (admin only)
+// @Tags Users / Пользователи
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} restoreUserResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/restore [post]
+func (r *UserRoutes) restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	err = r.service.RestoreUser(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, restoreUserResponse{
+		Success: true,
+	})
+}
+
+// @Summary Выгрузка данных пользователя
+// @Description Zip archive with the profile, tasks and audit records of the user (requires pii:read)
+// @Tags Users / Пользователи
+// @Produce application/zip
+// @Param id path int true "User ID"
+// @Success 200 {file} file
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/export [get]
+func (r *UserRoutes) export(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	export, err := r.service.ExportUser(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.User},
+		{"tasks.json", export.Tasks},
+		{"audit.json", export.AuditRecords},
+	}
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err == nil {
+			err = json.NewEncoder(w).Encode(file.data)
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-export.zip"`, id))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+type anonymizeUserResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Анонимизация пользователя
+// @Description Erase personal data of the user and its audit history keeping task durations for reports
+// @Description (requires admin)
+// @Tags Users / Пользователи
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} anonymizeUserResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/anonymize [post]
+func (r *UserRoutes) anonymize(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	err = r.service.AnonymizeUser(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserAnonymized) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, anonymizeUserResponse{
+		Success: true,
+	})
+}
+
+type duplicatesInput struct {
+	MinScore float64 `form:"minScore" binding:"omitempty,gt=0,lte=1"`
+}
+
+// @Summary Поиск дубликатов пользователя
+// @Description Users likely to be the same person, scored by name, surname, patronymic and address similarity
+// @Tags Users / Пользователи
+// @Produce json
+// @Param id path int true "User ID"
+// @Param minScore query number false "Minimal score from 0 to 1, 0.5 by default"
+// @Success 200 {array} model.DuplicateCandidate
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/duplicates [get]
+func (r *UserRoutes) duplicates(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input duplicatesInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	candidates, err := r.service.FindDuplicates(c, id, input.MinScore)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if candidates == nil {
+		candidates = []model.DuplicateCandidate{}
+	}
+	newResponse(c, http.StatusOK, candidates)
+}
+
+type mergeUserInput struct {
+	SourceID int `json:"sourceId" binding:"required,gt=0"`
+}
+
+type mergeUserResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Объединение пользователей
+// @Description Move the tasks of the source user to the user from the path and delete the source, invoiced tasks
+// @Description stay with the source (admin only)
+// @Tags Users / Пользователи
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user that stays"
+// @Param input body mergeUserInput true "Duplicate to merge"
+// @Success 200 {object} mergeUserResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/merge [post]
+func (r *UserRoutes) merge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input mergeUserInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = r.service.MergeUsers(c, input.SourceID, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrMergeSameUser) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, mergeUserResponse{
+		Success: true,
+	})
+}
+
+type changeUserStatusInput struct {
+	Status string `json:"status" binding:"required" enums:"active,suspended,terminated"`
+	// EffectiveFrom schedules the change, it takes effect immediately when omitted
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+type changeUserStatusResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Изменение статуса пользователя
+// @Description Move the user to a lifecycle status from the given date (admin only). Only active users get new tasks
+// @Tags Users / Пользователи
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param input body changeUserStatusInput true "Status change"
+// @Success 200 {object} changeUserStatusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/status [post]
+func (r *UserRoutes) changeStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	var input changeUserStatusInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !validUserStatus(input.Status) {
+		newErrorResponse(c, http.StatusBadRequest, "status is invalid")
+		return
+	}
+	if utf8.RuneCountInString(input.Reason) > 256 {
+		newErrorResponse(c, http.StatusBadRequest, "reason too long")
+		return
+	}
+
+	data := service.ChangeUserStatusInput{
+		Status: input.Status,
+		Reason: input.Reason,
+	}
+	if input.EffectiveFrom != nil {
+		data.EffectiveFrom = *input.EffectiveFrom
+	}
+	err = r.service.ChangeUserStatus(c, id, data)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, changeUserStatusResponse{
+		Success: true,
+	})
+}
+
+// @Summary История статусов пользователя
+// @Description Lifecycle status changes of the user ordered by effective date, scheduled ones included
+// @Tags Users / Пользователи
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} model.UserStatusChange
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/{id}/status [get]
+func (r *UserRoutes) statusHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	changes, err := r.service.ListUserStatusChanges(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if changes == nil {
+		changes = []model.UserStatusChange{}
+	}
+	c.JSON(http.StatusOK, changes)
+}
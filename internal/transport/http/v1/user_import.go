@@ -0,0 +1,229 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// maxImportSize is the largest import body in bytes, a larger one is rejected before it is parsed.
+const maxImportSize = 32 << 20
+
+// importUserRow is a CSV record or an NDJSON line with the fields of createUserInput.
+type importUserRow struct {
+	PassportNumber  string `json:"passportNumber"`
+	DocumentType    string `json:"documentType"`
+	DocumentCountry string `json:"documentCountry"`
+	Name            string `json:"name"`
+	Surname         string `json:"surname"`
+	Patronymic      string `json:"patronymic"`
+	Address         string `json:"address"`
+	Email           string `json:"email"`
+
+	line int // line in the uploaded file, reported back in the results
+}
+
+type importUsersInput struct {
+	DryRun bool `json:"dryRun,omitempty" form:"dryRun"`
+}
+
+type importUserResult struct {
+	Line             int    `json:"line"`
+	Status           string `json:"status"`
+	ID               int    `json:"id,omitempty"`
+	EnrichmentStatus string `json:"enrichmentStatus,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+type importUsersResponse struct {
+	DryRun  bool               `json:"dryRun"`
+	Summary map[string]int     `json:"summary"` // status -> rows
+	Results []importUserResult `json:"results"`
+}
+
+// @Summary Импорт пользователей
+// @Description Bulk import from CSV (header passportNumber,documentType,documentCountry,name,surname,patronymic,address,email,
+// @Description only passportNumber is required) or NDJSON of createUserInput objects.
+// @Description Rows with a name and surname are taken as is, the rest are enriched.
+// @Description Statuses: created, valid (dry run), exists, duplicate, invalid, failed
+// @Description The body is limited to USER_IMPORT_MAX_ROWS rows and 32 MiB, a larger one is rejected with 413
+// @Tags Users / Пользователи
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param dryRun query bool false "Validate and enrich rows without creating users"
+// @Success 200 {object} importUsersResponse
+// @Failure 400 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/import [post]
+func (r *UserRoutes) importUsers(c *gin.Context) {
+	var input importUsersInput
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var rows []importUserRow
+	var err error
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	switch c.ContentType() {
+	case mimeCSV:
+		rows, err = readImportCSV(body, r.service.ImportMaxRows())
+	case mimeNDJSON:
+		rows, err = readImportNDJSON(body, r.service.ImportMaxRows())
+	default:
+		newErrorResponse(c, http.StatusUnsupportedMediaType, "expected "+mimeCSV+" or "+mimeNDJSON)
+		return
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, service.ErrImportTooLarge) || errors.As(err, &maxBytesErr) {
+			newErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := make([]importUserResult, 0, len(rows))
+	valid := make([]service.ImportUserRow, 0, len(rows))
+	for _, row := range rows {
+		line := row.line
+		msg, ok := validateUpdateUser(updateUserInput{
+			Name:       &row.Name,
+			Surname:    &row.Surname,
+			Patronymic: &row.Patronymic,
+			Address:    &row.Address,
+			Email:      &row.Email,
+		})
+		if !ok {
+			results = append(results, importUserResult{Line: line, Status: service.ImportStatusInvalid, Error: msg})
+			continue
+		}
+		valid = append(valid, service.ImportUserRow{
+			Line: line,
+			CreateUserInput: service.CreateUserInput{
+				DocumentType:    row.DocumentType,
+				DocumentCountry: row.DocumentCountry,
+				PassportNumber:  row.PassportNumber,
+				Name:            row.Name,
+				Surname:         row.Surname,
+				Patronymic:      row.Patronymic,
+				Address:         row.Address,
+				Email:           row.Email,
+			},
+		})
+	}
+
+	imported, err := r.service.ImportUsers(c, valid, input.DryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrImportTooLarge) {
+			newErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, result := range imported {
+		results = append(results, importUserResult(result))
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+
+	summary := make(map[string]int)
+	for _, result := range results {
+		summary[result.Status]++
+	}
+	c.JSON(http.StatusOK, importUsersResponse{
+		DryRun:  input.DryRun,
+		Summary: summary,
+		Results: results,
+	})
+}
+
+func readImportCSV(body io.Reader, maxRows int) ([]importUserRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["passportNumber"]; !ok {
+		return nil, errors.New("csv header has no passportNumber column")
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []importUserRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read csv: %w", err)
+		}
+		if len(rows) == maxRows {
+			return nil, service.ErrImportTooLarge
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, importUserRow{
+			line:            line,
+			PassportNumber:  field(record, "passportNumber"),
+			DocumentType:    field(record, "documentType"),
+			DocumentCountry: field(record, "documentCountry"),
+			Name:            field(record, "name"),
+			Surname:         field(record, "surname"),
+			Patronymic:      field(record, "patronymic"),
+			Address:         field(record, "address"),
+			Email:           field(record, "email"),
+		})
+	}
+}
+
+func readImportNDJSON(body io.Reader, maxRows int) ([]importUserRow, error) {
+	var rows []importUserRow
+	scanner := bufio.NewScanner(body)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, service.ErrImportTooLarge
+		}
+		var row importUserRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		row.line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read ndjson: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time-tracker/internal/repository/repoerr"
+	"time-tracker/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookRoutes struct {
+	service service.Webhook
+}
+
+func newWebhookRoutes(handler *gin.RouterGroup, service service.Webhook) {
+	r := &WebhookRoutes{service}
+	handler.POST("", r.create)
+	handler.GET("", r.getList)
+	handler.DELETE(":id", r.delete)
+	handler.GET(":id/deliveries", r.getDeliveries)
+	handler.POST(":id/deliveries/:deliveryId/redeliver", r.redeliver)
+}
+
+type createWebhookInput struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events,omitempty" binding:"dive,oneof=task.created task.completed user.created user.deleted"`
+	Secret string   `json:"secret,omitempty" binding:"omitempty,min=16,max=256"`
+}
+
+// @Summary Создание подписки на вебхуки
+// @Description Subscribe URL to events, to all of them when events are empty. Every delivery is a POST with
+// @Description the event JSON signed by the X-Webhook-Signature header: "sha256=" and hex HMAC-SHA256 of
+// @Description "{X-Webhook-Timestamp}.{body}" with the secret. The secret is generated when not given and
+// @Description returned only here. Admin only
+// @Tags Webhooks / Вебхуки
+// @Accept json
+// @Produce json
+// @Param input body createWebhookInput true "Webhook input"
+// @Success 200 {object} model.WebhookSubscription
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/webhooks [post]
+func (r *WebhookRoutes) create(c *gin.Context) {
+	var input createWebhookInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	subscription, err := r.service.CreateSubscription(c, service.CreateWebhookInput{
+		URL:    input.URL,
+		Events: input.Events,
+		Secret: input.Secret,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// @Summary Получение списка подписок на вебхуки
+// @Description Webhook subscription list without secrets. Admin only
+// @Tags Webhooks / Вебхуки
+// @Produce json
+// @Success 200 {array} model.WebhookSubscription
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/webhooks [get]
+func (r *WebhookRoutes) getList(c *gin.Context) {
+	items, err := r.service.ListSubscriptions(c)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+type deleteWebhookResponse struct {
+	Success bool `json:"success"`
+}
+
+// @Summary Удаление подписки на вебхуки
+// @Description Delete webhook subscription with its deliveries. Admin only
+// @Tags Webhooks / Вебхуки
+// @Param id path int true "Subscription ID"
+// @Produce json
+// @Success 200 {object} deleteWebhookResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/webhooks/{id} [delete]
+func (r *WebhookRoutes) delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	err = r.service.DeleteSubscription(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, deleteWebhookResponse{Success: true})
+}
+
+// @Summary Получение доставок вебхука
+// @Description Last 100 deliveries of the subscription, newest first. Admin only
+// @Tags Webhooks / Вебхуки
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (r *WebhookRoutes) getDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	items, err := r.service.ListDeliveries(c, id)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Повторная доставка вебхука
+// @Description Queue a new delivery of the same event, it keeps the event ID so receivers can deduplicate.
+// @Description Admin only
+// @Tags Webhooks / Вебхуки
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 200 {object} model.WebhookDelivery
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (r *WebhookRoutes) redeliver(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+	deliveryID, err := strconv.Atoi(c.Param("deliveryId"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid delivery id param")
+		return
+	}
+	delivery, err := r.service.Redeliver(c, id, deliveryID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
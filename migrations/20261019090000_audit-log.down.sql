@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON md.audit_log;
+DROP FUNCTION IF EXISTS md.audit_log_append_only();
+DROP INDEX IF EXISTS md.idx_audit_log_entity;
+DROP TABLE IF EXISTS md.audit_log;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS md.audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(64) NOT NULL,
+    entity VARCHAR(32) NOT NULL,
+    entity_id INT NOT NULL,
+    "action" VARCHAR(32) NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON md.audit_log(entity, entity_id);
+
+CREATE OR REPLACE FUNCTION md.audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'md.audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON md.audit_log
+    FOR EACH ROW EXECUTE FUNCTION md.audit_log_append_only();
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS md.idx_task_deleted_at;
+DROP INDEX IF EXISTS md.idx_user_deleted_at;
+
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE md."users" DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_user_deleted_at ON md."users"(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_deleted_at ON md.tasks(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- encrypted values are kept as is, the column can't be narrowed back to VARCHAR(11).
+DROP INDEX IF EXISTS md.idx_user_passport_hash;
+ALTER TABLE md."users" DROP COLUMN IF EXISTS passport_hash;
+
+ALTER TABLE md."users" ADD CONSTRAINT users_passport_number_key UNIQUE (passport_number);
+CREATE INDEX IF NOT EXISTS idx_user_passport_number ON md."users"(passport_number);
@@ -0,0 +1,10 @@
+-- passport numbers become envelope encrypted, uniqueness and lookups move to the HMAC blind index.
+-- Existing plaintext rows are encrypted and hashed by the service at startup, `make reencrypt` only re-keys
+-- encrypted rows to the active key.
+ALTER TABLE md."users" DROP CONSTRAINT IF EXISTS users_passport_number_key;
+DROP INDEX IF EXISTS md.idx_user_passport_number;
+
+ALTER TABLE md."users" ALTER COLUMN passport_number TYPE TEXT;
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS passport_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_passport_hash ON md."users"(passport_hash);
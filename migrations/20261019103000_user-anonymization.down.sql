@@ -0,0 +1,7 @@
+CREATE OR REPLACE FUNCTION md.audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'md.audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE md."users" DROP COLUMN IF EXISTS anonymized_at;
@@ -0,0 +1,15 @@
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+-- anonymization is the only case where audit diffs may be rewritten, the statement sets md.audit_scrub locally
+CREATE OR REPLACE FUNCTION md.audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND current_setting('md.audit_scrub', true) = 'on'
+        AND (NEW.id, NEW.actor, NEW.entity, NEW.entity_id, NEW."action", NEW.created_at)
+            IS NOT DISTINCT FROM (OLD.id, OLD.actor, OLD.entity, OLD.entity_id, OLD."action", OLD.created_at)
+    THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'md.audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
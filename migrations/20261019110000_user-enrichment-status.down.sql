@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS md.idx_user_enrichment_pending;
+
+ALTER TABLE md."users" DROP COLUMN IF EXISTS enrichment_status;
@@ -0,0 +1,3 @@
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS enrichment_status VARCHAR(32) NOT NULL DEFAULT 'enriched';
+
+CREATE INDEX IF NOT EXISTS idx_user_enrichment_pending ON md."users"(id) WHERE enrichment_status = 'pending_enrichment';
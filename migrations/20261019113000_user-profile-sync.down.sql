@@ -0,0 +1,2 @@
+ALTER TABLE md."users" DROP COLUMN IF EXISTS synced_at;
+ALTER TABLE md."users" DROP COLUMN IF EXISTS profile_pinned;
@@ -0,0 +1,2 @@
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS profile_pinned BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS synced_at TIMESTAMPTZ;
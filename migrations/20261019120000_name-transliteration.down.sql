@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS md.search_key(TEXT);
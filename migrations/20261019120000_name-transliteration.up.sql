@@ -0,0 +1,10 @@
+-- md.search_key folds a name to a lower case latin key so that "Иванов", "Ivanov" and "IVANOV" match each other:
+-- cyrillic is transliterated as in russian passports (ICAO), then common latin spelling variants are folded.
+CREATE OR REPLACE FUNCTION md.search_key(value TEXT) RETURNS TEXT AS $$
+    SELECT replace(translate(replace(replace(translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(lower(value),
+            'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'), 'ш', 'sh'), 'ю', 'iu'), 'я', 'ia'),
+        'абвгдеёзийклмнопрстуфыэъь''’', 'abvgdeeziiklmnoprstufye'),
+        'kh', 'h'), 'x', 'ks'),
+        'jyw', 'iiv'), 'ie', 'e')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS md.idx_users_address_trgm;
+DROP INDEX IF EXISTS md.idx_users_patronymic_trgm;
+DROP INDEX IF EXISTS md.idx_users_surname_trgm;
+DROP INDEX IF EXISTS md.idx_users_username_trgm;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- serve both the transliterated LIKE filters and the fuzzy q= search
+CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON md."users" USING gin (md.search_key(username) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_surname_trgm ON md."users" USING gin (md.search_key(surname) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_patronymic_trgm ON md."users" USING gin (md.search_key(patronymic) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_address_trgm ON md."users" USING gin (lower("address") gin_trgm_ops);
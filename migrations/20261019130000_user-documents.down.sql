@@ -0,0 +1,2 @@
+ALTER TABLE md."users" DROP COLUMN IF EXISTS document_country;
+ALTER TABLE md."users" DROP COLUMN IF EXISTS document_type;
@@ -0,0 +1,4 @@
+-- users may be identified by other documents than the russian passport, passport_number keeps the document number.
+-- The blind index of russian passports stays the HMAC of the number, other documents hash type:country:number.
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS document_type VARCHAR(32) NOT NULL DEFAULT 'ru_passport';
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS document_country CHAR(2) NOT NULL DEFAULT 'RU';
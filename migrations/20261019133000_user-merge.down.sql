@@ -0,0 +1 @@
+ALTER TABLE md."users" DROP COLUMN IF EXISTS merged_into_id;
@@ -0,0 +1,2 @@
+-- a merged duplicate is soft deleted and points to the user that took over its tasks
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS merged_into_id INT REFERENCES md."users"(id) ON DELETE SET NULL;
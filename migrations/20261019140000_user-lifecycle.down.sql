@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS md.user_status(INT, TIMESTAMPTZ);
+DROP TABLE IF EXISTS md.user_statuses;
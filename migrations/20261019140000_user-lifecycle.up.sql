@@ -0,0 +1,21 @@
+-- lifecycle history of users, a change takes effect at effective_from and lasts until the next one
+CREATE TABLE IF NOT EXISTS md.user_statuses (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES md."users"(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('active', 'suspended', 'terminated')),
+    effective_from TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_statuses_user ON md.user_statuses(user_id, effective_from DESC);
+
+-- md.user_status is the status of the user at the given time, users without history are active
+CREATE OR REPLACE FUNCTION md.user_status(user_id INT, at TIMESTAMPTZ DEFAULT now()) RETURNS VARCHAR AS $$
+    SELECT COALESCE((
+        SELECT s.status FROM md.user_statuses s
+        WHERE s.user_id = $1 AND s.effective_from <= $2
+        ORDER BY s.effective_from DESC, s.id DESC
+        LIMIT 1
+    ), 'active')
+$$ LANGUAGE sql STABLE PARALLEL SAFE;
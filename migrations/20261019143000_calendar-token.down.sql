@@ -0,0 +1 @@
+ALTER TABLE md."users" DROP COLUMN IF EXISTS calendar_token_hash;
@@ -0,0 +1,2 @@
+-- blind index of the token that protects the calendar feed of the user, a new token replaces the old one
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS calendar_token_hash VARCHAR(64);
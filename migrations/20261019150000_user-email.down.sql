@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS md.idx_users_email;
+ALTER TABLE md."users" DROP COLUMN IF EXISTS email;
@@ -0,0 +1,3 @@
+ALTER TABLE md."users" ADD COLUMN IF NOT EXISTS email VARCHAR(254) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_users_email ON md."users"(lower(email)) WHERE email <> '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS md.idx_tasks_external_id;
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS external_id;
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS project_id;
+DROP TABLE IF EXISTS md.projects;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS md.projects (
+    id SERIAL PRIMARY KEY,
+    "name" VARCHAR(256) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_name ON md.projects(lower("name"));
+
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS project_id INT REFERENCES md.projects(id) ON DELETE SET NULL;
+-- id of the time entry in the tracker it was imported from, prefixed with the tracker name
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS external_id VARCHAR(128);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_external_id ON md.tasks(external_id);
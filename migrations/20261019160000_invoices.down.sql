@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS md.idx_tasks_invoice;
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS invoice_id;
+DROP TABLE IF EXISTS md.invoice_lines;
+DROP TABLE IF EXISTS md.invoices;
+DROP TABLE IF EXISTS md.invoice_counters;
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS billable;
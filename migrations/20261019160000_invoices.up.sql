@@ -0,0 +1,43 @@
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS billable BOOLEAN NOT NULL DEFAULT true;
+
+-- gapless invoice numbers, a number is taken in the transaction creating the invoice
+CREATE TABLE IF NOT EXISTS md.invoice_counters (
+    "year" INT PRIMARY KEY,
+    last_number INT NOT NULL
+);
+
+-- amounts are in minor currency units (kopecks), tax_rate is a percentage
+CREATE TABLE IF NOT EXISTS md.invoices (
+    id SERIAL PRIMARY KEY,
+    "number" VARCHAR(32) NOT NULL UNIQUE,
+    project_id INT REFERENCES md.projects(id) ON DELETE SET NULL,
+    issuer TEXT NOT NULL DEFAULT '',
+    customer TEXT NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    period_from TIMESTAMPTZ NOT NULL,
+    period_to TIMESTAMPTZ NOT NULL,
+    group_by VARCHAR(16) NOT NULL,
+    subtotal BIGINT NOT NULL,
+    tax_rate NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    tax BIGINT NOT NULL,
+    total BIGINT NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS md.invoice_lines (
+    id SERIAL PRIMARY KEY,
+    invoice_id INT NOT NULL REFERENCES md.invoices(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    "description" TEXT NOT NULL,
+    duration INT NOT NULL,
+    rate BIGINT NOT NULL,
+    amount BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_lines_invoice ON md.invoice_lines(invoice_id, position);
+
+-- a task is billed by at most one invoice
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS invoice_id INT REFERENCES md.invoices(id);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_invoice ON md.tasks(invoice_id);
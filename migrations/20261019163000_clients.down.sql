@@ -0,0 +1,4 @@
+ALTER TABLE md.invoices DROP COLUMN IF EXISTS client_id;
+DROP INDEX IF EXISTS md.idx_projects_client;
+ALTER TABLE md.projects DROP COLUMN IF EXISTS client_id;
+DROP TABLE IF EXISTS md.clients;
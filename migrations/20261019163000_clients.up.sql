@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS md.clients (
+    id SERIAL PRIMARY KEY,
+    "name" VARCHAR(256) NOT NULL,
+    contact_name VARCHAR(256) NOT NULL DEFAULT '',
+    email VARCHAR(256) NOT NULL DEFAULT '',
+    phone VARCHAR(64) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    currency CHAR(3) NOT NULL,
+    -- per hour in minor currency units, zero when the client has none
+    default_rate BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_name ON md.clients(lower("name"));
+
+ALTER TABLE md.projects ADD COLUMN IF NOT EXISTS client_id INT REFERENCES md.clients(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_client ON md.projects(client_id);
+
+ALTER TABLE md.invoices ADD COLUMN IF NOT EXISTS client_id INT REFERENCES md.clients(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS md.project_budget_alerts;
+DROP TABLE IF EXISTS md.project_budgets;
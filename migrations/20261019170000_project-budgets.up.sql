@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS md.project_budgets (
+    project_id INT PRIMARY KEY REFERENCES md.projects(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    "period" VARCHAR(16) NOT NULL,
+    -- minutes for hours budgets, minor currency units for money budgets
+    "limit" BIGINT NOT NULL,
+    -- per hour in minor currency units, money budgets only
+    rate BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ
+);
+
+-- thresholds the consumption crossed, once per budget period
+CREATE TABLE IF NOT EXISTS md.project_budget_alerts (
+    id SERIAL PRIMARY KEY,
+    project_id INT NOT NULL REFERENCES md.project_budgets(project_id) ON DELETE CASCADE,
+    period_key VARCHAR(16) NOT NULL,
+    threshold INT NOT NULL,
+    consumed BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    notified_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_budget_alerts_threshold ON md.project_budget_alerts(project_id, period_key, threshold);
+CREATE INDEX IF NOT EXISTS idx_project_budget_alerts_pending ON md.project_budget_alerts(id) WHERE notified_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS md.webhook_deliveries;
+DROP TABLE IF EXISTS md.webhook_subscriptions;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS md.webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    -- empty for every event
+    events TEXT[] NOT NULL DEFAULT '{}',
+    secret VARCHAR(256) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS md.webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    subscription_id INT NOT NULL REFERENCES md.webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id VARCHAR(64) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    response_status INT,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON md.webhook_deliveries(subscription_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON md.webhook_deliveries(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS md.outbox (
+    id BIGSERIAL PRIMARY KEY,
+    -- events of an aggregate are published in the order of their ids
+    aggregate VARCHAR(32) NOT NULL,
+    aggregate_id INT NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    -- user events carry the id, status and dates only, no personal data
+    payload JSONB NOT NULL CHECK (aggregate <> 'user' OR NOT payload ?| ARRAY['name', 'surname', 'patronymic']),
+    attempts INT NOT NULL DEFAULT 0,
+    -- NULL once published
+    next_attempt_at TIMESTAMPTZ DEFAULT now(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON md.outbox(id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate ON md.outbox(aggregate, aggregate_id, id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published ON md.outbox(published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,158 @@
+// Package document validates identity documents through a registry of document types.
+package document
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	TypeRUPassport      = "ru_passport"
+	TypeForeignPassport = "foreign_passport"
+	TypeResidencePermit = "residence_permit"
+)
+
+// ErrInvalid wraps every validation failure.
+var ErrInvalid = errors.New("invalid document")
+
+type Document struct {
+	Type    string
+	Country string // ISO 3166-1 alpha-2 code of the issuing country
+	Number  string
+}
+
+type Type struct {
+	Name string
+	// Countries that issue the document, the first one is the default. Empty means any country, which then must be given.
+	Countries []string
+	Pattern   *regexp.Regexp
+	// CountryPatterns override Pattern for documents issued by the given countries
+	CountryPatterns map[string]*regexp.Regexp
+	// Lookup is set when people info providers can enrich holders of the document
+	Lookup bool
+}
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type Registry struct {
+	defaultType string
+	types       map[string]Type
+}
+
+// NewRegistry registers types, the first one is used for documents without a type.
+func NewRegistry(types ...Type) *Registry {
+	r := &Registry{types: make(map[string]Type, len(types))}
+	for _, t := range types {
+		r.Register(t)
+	}
+	return r
+}
+
+// DefaultRegistry knows the russian passport (the default type), foreign passports and russian residence permits.
+func DefaultRegistry() *Registry {
+	return NewRegistry(
+		Type{
+			Name:      TypeRUPassport,
+			Countries: []string{"RU"},
+			Pattern:   regexp.MustCompile(`^\d{4} \d{6}$`),
+			Lookup:    true,
+		},
+		Type{
+			Name:    TypeForeignPassport,
+			Pattern: regexp.MustCompile(`^[A-Z0-9]{6,9}$`),
+			CountryPatterns: map[string]*regexp.Regexp{
+				"RU": regexp.MustCompile(`^\d{2} \d{7}$`),
+				"US": regexp.MustCompile(`^[A-Z0-9]{9}$`),
+				"DE": regexp.MustCompile(`^[CFGHJKLMNPRTVWXYZ0-9]{9}$`),
+			},
+		},
+		Type{
+			Name:      TypeResidencePermit,
+			Countries: []string{"RU"},
+			Pattern:   regexp.MustCompile(`^\d{2} \d{7}$`),
+		},
+	)
+}
+
+func (r *Registry) Register(t Type) {
+	if r.defaultType == "" {
+		r.defaultType = t.Name
+	}
+	r.types[t.Name] = t
+}
+
+// Validate fills the default type and country, normalizes the number and checks it against the type.
+func (r *Registry) Validate(doc Document) (Document, error) {
+	if doc.Type == "" {
+		doc.Type = r.defaultType
+	}
+	t, ok := r.types[doc.Type]
+	if !ok {
+		return doc, fmt.Errorf("%w: unknown document type %q", ErrInvalid, doc.Type)
+	}
+
+	doc.Country = strings.ToUpper(strings.TrimSpace(doc.Country))
+	if doc.Country == "" && len(t.Countries) > 0 {
+		doc.Country = t.Countries[0]
+	}
+	if !countryPattern.MatchString(doc.Country) {
+		return doc, fmt.Errorf("%w: invalid country %q", ErrInvalid, doc.Country)
+	}
+	if len(t.Countries) > 0 && !contains(t.Countries, doc.Country) {
+		return doc, fmt.Errorf("%w: %s is not issued by %s", ErrInvalid, doc.Type, doc.Country)
+	}
+
+	doc.Number = strings.ToUpper(strings.TrimSpace(doc.Number))
+	pattern, ok := t.CountryPatterns[doc.Country]
+	if !ok {
+		pattern = t.Pattern
+	}
+	if !pattern.MatchString(doc.Number) {
+		return doc, fmt.Errorf("%w: %s number is invalid", ErrInvalid, doc.Type)
+	}
+	return doc, nil
+}
+
+// Patterns returns the number patterns of every registered type, country overrides included.
+func (r *Registry) Patterns() []*regexp.Regexp {
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var patterns []*regexp.Regexp
+	for _, name := range names {
+		t := r.types[name]
+		patterns = append(patterns, t.Pattern)
+		countries := make([]string, 0, len(t.CountryPatterns))
+		for country := range t.CountryPatterns {
+			countries = append(countries, country)
+		}
+		sort.Strings(countries)
+		for _, country := range countries {
+			patterns = append(patterns, t.CountryPatterns[country])
+		}
+	}
+	return patterns
+}
+
+// Lookup reports whether people info providers can enrich holders of the document type.
+func (r *Registry) Lookup(docType string) bool {
+	if docType == "" {
+		docType = r.defaultType
+	}
+	return r.types[docType].Lookup
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
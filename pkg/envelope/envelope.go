@@ -0,0 +1,207 @@
+// Package envelope implements envelope encryption of short string values.
+//
+// Every value is sealed with its own random data key (AES-256-GCM), the data key
+// is wrapped with a versioned key encryption key, so rotating the key encryption
+// key only needs the data keys to be rewrapped.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	version     = "v1"
+	dataKeySize = 32
+)
+
+var (
+	ErrNotEncrypted = errors.New("envelope: value is not encrypted")
+	ErrUnknownKey   = errors.New("envelope: unknown key")
+	ErrMalformed    = errors.New("envelope: malformed value")
+)
+
+type Envelope struct {
+	keys          map[string][]byte
+	activeKeyID   string
+	blindIndexKey []byte
+}
+
+// New accepts base64 encoded 32 byte keys indexed by key id, new values are encrypted with activeKeyID.
+func New(keys map[string]string, activeKeyID, blindIndexKey string) (*Envelope, error) {
+	e := &Envelope{
+		keys:        make(map[string][]byte, len(keys)),
+		activeKeyID: activeKeyID,
+	}
+
+	for id, encoded := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("envelope - New: invalid key id %q", id)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("envelope - New - key %q: %w", id, err)
+		}
+		e.keys[id] = key
+	}
+	if _, ok := e.keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("envelope - New: active key %q: %w", activeKeyID, ErrUnknownKey)
+	}
+
+	indexKey, err := decodeKey(blindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("envelope - New - blind index key: %w", err)
+	}
+	e.blindIndexKey = indexKey
+
+	return e, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// Encrypt returns "v1:<key id>:<wrapped data key>:<ciphertext>".
+func (e *Envelope) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("envelope - Encrypt - rand.Read: %w", err)
+	}
+
+	wrapped, err := seal(e.keys[e.activeKeyID], dataKey, []byte(e.activeKeyID))
+	if err != nil {
+		return "", fmt.Errorf("envelope - Encrypt - wrap: %w", err)
+	}
+	sealed, err := seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return "", fmt.Errorf("envelope - Encrypt - seal: %w", err)
+	}
+
+	return format(e.activeKeyID, wrapped, sealed), nil
+}
+
+func (e *Envelope) Decrypt(value string) (string, error) {
+	_, dataKey, sealed, err := e.unwrap(value)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("envelope - Decrypt - open: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether the value is plaintext or was wrapped with a key other than the active one.
+func (e *Envelope) NeedsRotation(value string) bool {
+	parts := strings.Split(value, ":")
+	return len(parts) != 4 || parts[0] != version || parts[1] != e.activeKeyID
+}
+
+// Rewrap wraps the data key of value with the active key, the ciphertext itself is kept.
+func (e *Envelope) Rewrap(value string) (string, error) {
+	_, dataKey, sealed, err := e.unwrap(value)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(e.keys[e.activeKeyID], dataKey, []byte(e.activeKeyID))
+	if err != nil {
+		return "", fmt.Errorf("envelope - Rewrap - wrap: %w", err)
+	}
+
+	return format(e.activeKeyID, wrapped, sealed), nil
+}
+
+// BlindIndex returns a keyed hash of value that allows equality lookups without decryption.
+func (e *Envelope) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, e.blindIndexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func format(keyID string, wrapped, sealed []byte) string {
+	return strings.Join([]string{
+		version,
+		keyID,
+		base64.RawStdEncoding.EncodeToString(wrapped),
+		base64.RawStdEncoding.EncodeToString(sealed),
+	}, ":")
+}
+
+func (e *Envelope) unwrap(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 || parts[0] != version {
+		return "", nil, nil, ErrNotEncrypted
+	}
+
+	keyID := parts[1]
+	key, ok := e.keys[keyID]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("envelope - unwrap - key %q: %w", keyID, ErrUnknownKey)
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+
+	dataKey, err := open(key, wrapped, []byte(keyID))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("envelope - unwrap - open: %w", err)
+	}
+	return keyID, dataKey, sealed, nil
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,215 @@
+package envelope
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func newTestEnvelope(t *testing.T, activeKeyID string) *Envelope {
+	t.Helper()
+	e, err := New(map[string]string{"k1": testKey('a'), "k2": testKey('b')}, activeKeyID, testKey('c'))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return e
+}
+
+func TestRoundTrip(t *testing.T) {
+	e := newTestEnvelope(t, "k1")
+	rotated := newTestEnvelope(t, "k2")
+
+	tests := []struct {
+		name      string
+		plaintext string
+	}{
+		{"passport", "1234 567890"},
+		{"empty", ""},
+		{"unicode", "АБ 123456"},
+		{"separators", "a:b:c:d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := e.Encrypt(tt.plaintext)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			if !strings.HasPrefix(encrypted, "v1:k1:") {
+				t.Errorf("Encrypt = %q, want the v1:k1: prefix", encrypted)
+			}
+			if tt.plaintext != "" && strings.Contains(encrypted, tt.plaintext) {
+				t.Errorf("Encrypt = %q contains the plaintext", encrypted)
+			}
+			if e.NeedsRotation(encrypted) {
+				t.Error("NeedsRotation = true for a value of the active key")
+			}
+
+			decrypted, err := e.Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if decrypted != tt.plaintext {
+				t.Errorf("Decrypt = %q, want %q", decrypted, tt.plaintext)
+			}
+
+			if !rotated.NeedsRotation(encrypted) {
+				t.Error("NeedsRotation = false for a value of a retired key")
+			}
+			rewrapped, err := rotated.Rewrap(encrypted)
+			if err != nil {
+				t.Fatalf("Rewrap: %v", err)
+			}
+			if !strings.HasPrefix(rewrapped, "v1:k2:") {
+				t.Errorf("Rewrap = %q, want the v1:k2: prefix", rewrapped)
+			}
+			if rotated.NeedsRotation(rewrapped) {
+				t.Error("NeedsRotation = true for a rewrapped value")
+			}
+			// the ciphertext is kept, only the data key is wrapped again
+			if got, want := strings.Split(rewrapped, ":")[3], strings.Split(encrypted, ":")[3]; got != want {
+				t.Errorf("Rewrap changed the ciphertext %q to %q", want, got)
+			}
+			decrypted, err = rotated.Decrypt(rewrapped)
+			if err != nil {
+				t.Fatalf("Decrypt of the rewrapped value: %v", err)
+			}
+			if decrypted != tt.plaintext {
+				t.Errorf("Decrypt of the rewrapped value = %q, want %q", decrypted, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptIsRandomized(t *testing.T) {
+	e := newTestEnvelope(t, "k1")
+	first, err := e.Encrypt("1234 567890")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	second, err := e.Encrypt("1234 567890")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if first == second {
+		t.Error("Encrypt returned the same value twice")
+	}
+}
+
+func TestLegacyPlaintext(t *testing.T) {
+	e := newTestEnvelope(t, "k1")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"passport", "1234 567890"},
+		{"empty", ""},
+		{"too few parts", "v1:k1:abc"},
+		{"too many parts", "v1:k1:a:b:c"},
+		{"other version", "v2:k1:abc:def"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.Decrypt(tt.value)
+			if !errors.Is(err, ErrNotEncrypted) {
+				t.Errorf("Decrypt error = %v, want ErrNotEncrypted", err)
+			}
+			if _, err := e.Rewrap(tt.value); !errors.Is(err, ErrNotEncrypted) {
+				t.Errorf("Rewrap error = %v, want ErrNotEncrypted", err)
+			}
+			if !e.NeedsRotation(tt.value) {
+				t.Error("NeedsRotation = false for a plaintext value")
+			}
+		})
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	e := newTestEnvelope(t, "k1")
+	encrypted, err := e.Encrypt("1234 567890")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	parts := strings.Split(encrypted, ":")
+
+	tests := []struct {
+		name  string
+		value string
+		want  error
+	}{
+		{"unknown key", strings.Join([]string{parts[0], "k9", parts[2], parts[3]}, ":"), ErrUnknownKey},
+		{"malformed data key", strings.Join([]string{parts[0], parts[1], "!!", parts[3]}, ":"), ErrMalformed},
+		{"malformed ciphertext", strings.Join([]string{parts[0], parts[1], parts[2], "!!"}, ":"), ErrMalformed},
+		{"truncated ciphertext", strings.Join([]string{parts[0], parts[1], parts[2], "AAAA"}, ":"), ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.Decrypt(tt.value)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Decrypt error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// the data key is bound to its key id, a value relabelled with another known key does not open
+	relabelled := strings.Join([]string{parts[0], "k2", parts[2], parts[3]}, ":")
+	if _, err := e.Decrypt(relabelled); err == nil {
+		t.Error("Decrypt of a value relabelled with another key succeeded")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          map[string]string
+		activeKeyID   string
+		blindIndexKey string
+		wantErr       error
+	}{
+		{"valid", map[string]string{"k1": testKey('a')}, "k1", testKey('c'), nil},
+		{"unknown active key", map[string]string{"k1": testKey('a')}, "k2", testKey('c'), ErrUnknownKey},
+		{"short key", map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))}, "k1", testKey('c'), errAny},
+		{"not base64", map[string]string{"k1": "REPLACE_WITH_BASE64_32_BYTE_KEY"}, "k1", testKey('c'), errAny},
+		{"key id with separator", map[string]string{"k:1": testKey('a')}, "k:1", testKey('c'), errAny},
+		{"invalid blind index key", map[string]string{"k1": testKey('a')}, "k1", "", errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.keys, tt.activeKeyID, tt.blindIndexKey)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("New error = %v, want nil", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Error("New error = nil, want an error")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Errorf("New error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// errAny expects an error without a sentinel to match.
+var errAny = errors.New("any error")
+
+func TestBlindIndex(t *testing.T) {
+	e := newTestEnvelope(t, "k1")
+	other, err := New(map[string]string{"k1": testKey('a')}, "k1", testKey('d'))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if e.BlindIndex("1234 567890") != e.BlindIndex("1234 567890") {
+		t.Error("BlindIndex is not deterministic")
+	}
+	if e.BlindIndex("1234 567890") == e.BlindIndex("1234 567891") {
+		t.Error("BlindIndex is the same for different values")
+	}
+	if e.BlindIndex("1234 567890") == other.BlindIndex("1234 567890") {
+		t.Error("BlindIndex does not depend on the key")
+	}
+}
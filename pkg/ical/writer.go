@@ -0,0 +1,153 @@
+// Package ical writes iCalendar (RFC 5545) feeds with events in a single time zone.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxLineLength = 75 // octets, longer content lines are folded
+	localLayout   = "20060102T150405"
+	utcLayout     = "20060102T150405Z"
+)
+
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Modified    time.Time
+}
+
+type Writer struct {
+	w   *bufio.Writer
+	loc *time.Location
+	now time.Time
+}
+
+// NewWriter starts the calendar and describes loc for the period from-to, events must fall within it.
+func NewWriter(w io.Writer, name string, loc *time.Location, from, to time.Time) (*Writer, error) {
+	cw := &Writer{w: bufio.NewWriter(w), loc: loc, now: time.Now().UTC()}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//time-tracker//calendar//RU")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	cw.line("X-WR-CALNAME:" + escape(name))
+	cw.line("X-WR-TIMEZONE:" + loc.String())
+	cw.timezone(from, to)
+	return cw, cw.w.Flush()
+}
+
+func (w *Writer) WriteEvent(e Event) error {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + escape(e.UID))
+	w.line("DTSTAMP:" + w.now.Format(utcLayout))
+	w.line(w.localTime("DTSTART", e.Start))
+	w.line(w.localTime("DTEND", e.End))
+	if !e.Modified.IsZero() {
+		w.line("LAST-MODIFIED:" + e.Modified.UTC().Format(utcLayout))
+	}
+	w.line("SUMMARY:" + escape(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escape(e.Description))
+	}
+	w.line("TRANSP:OPAQUE")
+	return w.line("END:VEVENT")
+}
+
+func (w *Writer) Close() error {
+	w.line("END:VCALENDAR")
+	return w.w.Flush()
+}
+
+func (w *Writer) localTime(property string, t time.Time) string {
+	return fmt.Sprintf("%s;TZID=%s:%s", property, w.loc.String(), t.In(w.loc).Format(localLayout))
+}
+
+// timezone writes a VTIMEZONE with an observance per UTC offset in effect between from and to. Go does not
+// expose the zone rules, so the offset changes are found by probing the location.
+func (w *Writer) timezone(from, to time.Time) {
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + w.loc.String())
+
+	from = from.Truncate(time.Second)
+	_, offset := from.In(w.loc).Zone()
+	w.observance(from, offset)
+	for t := from.Add(time.Hour); !t.After(to.Add(time.Hour)); t = t.Add(time.Hour) {
+		if _, next := t.In(w.loc).Zone(); next != offset {
+			w.observance(findChange(w.loc, t.Add(-time.Hour), t), offset)
+			offset = next
+		}
+	}
+	w.line("END:VTIMEZONE")
+}
+
+// observance starts at t, its DTSTART is the local time in the offset in effect before t.
+func (w *Writer) observance(t time.Time, offsetFrom int) {
+	local := t.In(w.loc)
+	name, offsetTo := local.Zone()
+	kind := "STANDARD"
+	if local.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + t.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(localLayout))
+	w.line("TZOFFSETFROM:" + formatOffset(offsetFrom))
+	w.line("TZOFFSETTO:" + formatOffset(offsetTo))
+	w.line("TZNAME:" + escape(name))
+	w.line("END:" + kind)
+}
+
+// findChange narrows the first second after before with a different offset down by bisection.
+func findChange(loc *time.Location, before, after time.Time) time.Time {
+	_, offset := before.In(loc).Zone()
+	for after.Sub(before) > time.Second {
+		mid := before.Add(after.Sub(before) / 2).Truncate(time.Second)
+		if _, o := mid.In(loc).Zone(); o == offset {
+			before = mid
+		} else {
+			after = mid
+		}
+	}
+	return after
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 sequences. Write errors are sticky,
+// so they are checked only where the caller gets them.
+func (w *Writer) line(content string) error {
+	limit := maxLineLength
+	for len(content) > limit {
+		cut := limit
+		for !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.w.WriteString(content[:cut])
+		w.w.WriteString("\r\n ")
+		content = content[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = maxLineLength - 1
+	}
+	_, err := w.w.WriteString(content + "\r\n")
+	return err
+}
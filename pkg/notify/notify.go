@@ -0,0 +1,97 @@
+// Package notify delivers notifications about the tracked time to the log or a webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Notification struct {
+	Event string    `json:"event"`
+	Text  string    `json:"text"`
+	Data  any       `json:"data,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+type multi []Notifier
+
+// Multi notifies through every notifier, the notification is undelivered if any of them failed.
+func Multi(notifiers ...Notifier) Notifier {
+	if len(notifiers) == 1 {
+		return notifiers[0]
+	}
+	return multi(notifiers)
+}
+
+func (m multi) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes notifications to the application log as warnings.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Notify(_ context.Context, n Notification) error {
+	entry := log.WithField("event", n.Event)
+	if n.Data != nil {
+		data, err := json.Marshal(n.Data)
+		if err != nil {
+			return fmt.Errorf("notify - Log.Notify - json.Marshal: %w", err)
+		}
+		entry = entry.WithField("data", string(data))
+	}
+	entry.Warn(n.Text)
+	return nil
+}
+
+// Webhook posts notifications as JSON, any status but 2xx fails the delivery.
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("notify - Webhook.Notify - json.Marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify - Webhook.Notify - http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify - Webhook.Notify - httpClient.Do: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("notify - Webhook.Notify: %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+// Package pdf writes simple PDF documents: text in a single embedded TrueType font, lines and filled rectangles
+// on A4 pages. Coordinates are in points from the bottom left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	PageWidth  = 595.28 // A4 in points
+	PageHeight = 841.89
+)
+
+type Document struct {
+	font  *Font
+	title string
+	pages []*Page
+	// glyphs used by the pages and the text they stand for, for the font subset and text extraction
+	used map[uint16]rune
+}
+
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+func NewDocument(font *Font, title string) *Document {
+	return &Document{font: font, title: title, used: make(map[uint16]rune)}
+}
+
+func (d *Document) Font() *Font {
+	return d.font
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text draws text with its baseline starting at x, y. gray is 0 for black to 1 for white.
+func (p *Page) Text(x, y, size, gray float64, text string) {
+	var glyphs strings.Builder
+	for _, r := range text {
+		gid := p.doc.font.glyph(r)
+		if _, ok := p.doc.used[gid]; !ok {
+			p.doc.used[gid] = r
+		}
+		fmt.Fprintf(&glyphs, "%04X", gid)
+	}
+	fmt.Fprintf(&p.content, "BT %.3f g /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", gray, size, x, y, glyphs.String())
+}
+
+// TextRight draws text ending at x.
+func (p *Page) TextRight(x, y, size, gray float64, text string) {
+	p.Text(x-p.doc.font.Width(text, size), y, size, gray, text)
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width, gray float64) {
+	fmt.Fprintf(&p.content, "%.3f G %.2f w %.2f %.2f m %.2f %.2f l S\n", gray, width, x1, y1, x2, y2)
+}
+
+func (p *Page) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "%.3f g %.2f %.2f %.2f %.2f re f\n", gray, x, y, width, height)
+}
+
+// Wrap breaks text into lines no wider than width, at spaces where possible.
+func (d *Document) Wrap(text string, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if d.font.Width(candidate, size) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// a word longer than the line is broken anywhere
+			line = ""
+			for _, r := range word {
+				if line != "" && d.font.Width(line+string(r), size) > width {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+type objectWriter struct {
+	w       *countingWriter
+	offsets []int64
+}
+
+func (o *objectWriter) begin(id int) {
+	for len(o.offsets) < id {
+		o.offsets = append(o.offsets, 0)
+	}
+	o.offsets[id-1] = o.w.n
+	fmt.Fprintf(o.w, "%d 0 obj\n", id)
+}
+
+func (o *objectWriter) object(id int, format string, args ...any) {
+	o.begin(id)
+	fmt.Fprintf(o.w, format, args...)
+	io.WriteString(o.w, "\nendobj\n")
+}
+
+// stream writes data deflated, extra are additional dictionary entries.
+func (o *objectWriter) stream(id int, data []byte, extra string) {
+	var deflated bytes.Buffer
+	z := zlib.NewWriter(&deflated)
+	z.Write(data)
+	z.Close()
+
+	o.begin(id)
+	fmt.Fprintf(o.w, "<< /Length %d /Filter /FlateDecode%s >>\nstream\n", deflated.Len(), extra)
+	o.w.Write(deflated.Bytes())
+	io.WriteString(o.w, "\nendstream\nendobj\n")
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// WriteTo writes the document with the subset of the font its pages use.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	const (
+		catalogID = iota + 1
+		pagesID
+		infoID
+		fontID
+		cidFontID
+		descriptorID
+		fontFileID
+		toUnicodeID
+		firstPageID
+	)
+	cw := &countingWriter{w: w}
+	o := &objectWriter{w: cw}
+	io.WriteString(cw, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageID+2*i)
+	}
+	o.object(catalogID, "<< /Type /Catalog /Pages %d 0 R >>", pagesID)
+	o.object(pagesID, "<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+	o.object(infoID, "<< /Title %s /Producer (time-tracker) /CreationDate (D:%s) >>",
+		textString(d.title), time.Now().UTC().Format("20060102150405Z"))
+
+	f := d.font
+	gids := make([]int, 0, len(d.used))
+	for gid := range d.used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+	// subsets are tagged with six capital letters, derived from the glyphs so that different subsets differ
+	hash := fnv.New32a()
+	for _, gid := range gids {
+		hash.Write([]byte{byte(gid >> 8), byte(gid)})
+	}
+	tag, sum := make([]byte, 6), hash.Sum32()
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	baseFont := string(tag) + "+" + f.name
+
+	o.object(fontID, "<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		baseFont, cidFontID, toUnicodeID)
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, f.scale(f.advances[gid]))
+	}
+	o.object(cidFontID, "<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /DW %d /W [%s] /CIDToGIDMap /Identity >>",
+		baseFont, descriptorID, f.scale(f.advances[0]), widths.String())
+	o.object(descriptorID, "<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		baseFont, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.capHeight), fontFileID)
+
+	used := make(map[uint16]bool, len(gids))
+	for _, gid := range gids {
+		used[uint16(gid)] = true
+	}
+	subset := f.subset(used)
+	o.stream(fontFileID, subset, fmt.Sprintf(" /Length1 %d", len(subset)))
+	o.stream(toUnicodeID, d.toUnicode(gids), "")
+
+	for i, p := range d.pages {
+		pageID := firstPageID + 2*i
+		o.object(pageID, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesID, PageWidth, PageHeight, fontID, pageID+1)
+		o.stream(pageID+1, p.content.Bytes(), "")
+	}
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(o.offsets)+1)
+	for _, offset := range o.offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(o.offsets)+1, catalogID, infoID, xref)
+	return cw.n, cw.err
+}
+
+// toUnicode maps the glyphs back to text, so that it can be searched and copied.
+func (d *Document) toUnicode(gids []int) []byte {
+	var cmap bytes.Buffer
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(gids); start += 100 {
+		chunk := gids[start:min(start+100, len(gids))]
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", len(chunk))
+		for _, gid := range chunk {
+			fmt.Fprintf(&cmap, "<%04X> <", gid)
+			for _, unit := range utf16.Encode([]rune{d.used[uint16(gid)]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return cmap.Bytes()
+}
+
+// textString encodes text outside of content streams as UTF-16 with a byte order mark.
+func textString(text string) string {
+	var s strings.Builder
+	s.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&s, "%04X", unit)
+	}
+	s.WriteString(">")
+	return s.String()
+}
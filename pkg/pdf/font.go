@@ -0,0 +1,339 @@
+package pdf
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//go:embed fonts/DejaVuSans.ttf
+var dejaVuSans []byte
+
+var (
+	defaultFont     *Font
+	defaultFontErr  error
+	defaultFontOnce sync.Once
+)
+
+// DefaultFont is DejaVu Sans, which covers latin and cyrillic, see fonts/LICENSE.
+func DefaultFont() (*Font, error) {
+	defaultFontOnce.Do(func() {
+		defaultFont, defaultFontErr = ParseFont("DejaVuSans", dejaVuSans)
+	})
+	return defaultFont, defaultFontErr
+}
+
+// Font is a TrueType font, documents embed the subset of the glyphs they use.
+type Font struct {
+	name   string
+	tables map[string][]byte
+
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	capHeight  int
+
+	advances []int
+	glyphs   map[rune]uint16
+	loca     []int
+}
+
+var errInvalidFont = errors.New("pdf: invalid truetype font")
+
+// ParseFont reads the metrics and the character map of a TrueType font, name becomes its PostScript name.
+func ParseFont(name string, data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, errInvalidFont
+	}
+	f := &Font{name: name, tables: make(map[string][]byte)}
+	numTables := int(u16(data, 4))
+	for i := 0; i < numTables; i++ {
+		entry := 12 + 16*i
+		if entry+16 > len(data) {
+			return nil, errInvalidFont
+		}
+		offset, length := int(u32(data, entry+8)), int(u32(data, entry+12))
+		if offset+length > len(data) {
+			return nil, errInvalidFont
+		}
+		f.tables[string(data[entry:entry+4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "loca", "glyf"} {
+		if f.tables[tag] == nil {
+			return nil, fmt.Errorf("%w: no %s table", errInvalidFont, tag)
+		}
+	}
+
+	head := f.tables["head"]
+	f.unitsPerEm = int(u16(head, 18))
+	for i := range f.bbox {
+		f.bbox[i] = int(int16(u16(head, 36+2*i)))
+	}
+	hhea := f.tables["hhea"]
+	f.ascent, f.descent = int(int16(u16(hhea, 4))), int(int16(u16(hhea, 6)))
+	f.capHeight = f.ascent
+	if os2 := f.tables["OS/2"]; len(os2) >= 90 && u16(os2, 0) >= 2 {
+		f.capHeight = int(int16(u16(os2, 88)))
+	}
+
+	numGlyphs := int(u16(f.tables["maxp"], 4))
+	hmtx, numMetrics := f.tables["hmtx"], int(u16(hhea, 34))
+	if numMetrics == 0 || len(hmtx) < 4*numMetrics {
+		return nil, errInvalidFont
+	}
+	f.advances = make([]int, numGlyphs)
+	for gid := range f.advances {
+		f.advances[gid] = int(u16(hmtx, 4*min(gid, numMetrics-1)))
+	}
+
+	loca, long := f.tables["loca"], u16(head, 50) == 1
+	f.loca = make([]int, numGlyphs+1)
+	for i := range f.loca {
+		if long && 4*i+4 <= len(loca) {
+			f.loca[i] = int(u32(loca, 4*i))
+		} else if !long && 2*i+2 <= len(loca) {
+			f.loca[i] = 2 * int(u16(loca, 2*i))
+		} else {
+			return nil, errInvalidFont
+		}
+	}
+
+	var err error
+	f.glyphs, err = parseCmap(f.tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseCmap reads the unicode subtable, the full repertoire (format 12) is preferred to the BMP one (format 4).
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	var bmp, full []byte
+	for i := 0; i < int(u16(cmap, 2)); i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			return nil, errInvalidFont
+		}
+		platform, encoding, offset := u16(cmap, record), u16(cmap, record+2), int(u32(cmap, record+4))
+		if offset >= len(cmap) || (platform != 0 && platform != 3) {
+			continue
+		}
+		switch format := u16(cmap, offset); {
+		case format == 12:
+			full = cmap[offset:]
+		case format == 4 && (platform == 0 || encoding == 1):
+			bmp = cmap[offset:]
+		}
+	}
+
+	glyphs := make(map[rune]uint16)
+	switch {
+	case full != nil:
+		for i := 0; i < int(u32(full, 12)); i++ {
+			group := 16 + 12*i
+			start, end, gid := u32(full, group), u32(full, group+4), u32(full, group+8)
+			for r := start; r <= end; r++ {
+				glyphs[rune(r)] = uint16(gid + r - start)
+			}
+		}
+	case bmp != nil:
+		segments := int(u16(bmp, 6)) / 2
+		ends, starts := 14, 16+2*segments
+		deltas, rangeOffsets := starts+2*segments, starts+4*segments
+		for i := 0; i < segments; i++ {
+			start, end := u16(bmp, starts+2*i), u16(bmp, ends+2*i)
+			delta, rangeOffset := u16(bmp, deltas+2*i), int(u16(bmp, rangeOffsets+2*i))
+			for r := int(start); r <= int(end) && r != 0xFFFF; r++ {
+				gid := uint16(r) + delta
+				if rangeOffset != 0 {
+					// the offset is relative to its own position in the idRangeOffset array
+					at := rangeOffsets + 2*i + rangeOffset + 2*(r-int(start))
+					if at+2 > len(bmp) {
+						return nil, errInvalidFont
+					}
+					if gid = u16(bmp, at); gid != 0 {
+						gid += delta
+					}
+				}
+				if gid != 0 {
+					glyphs[rune(r)] = gid
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: no unicode character map", errInvalidFont)
+	}
+	return glyphs, nil
+}
+
+// glyph returns the glyph of r, zero (.notdef) when the font lacks it.
+func (f *Font) glyph(r rune) uint16 {
+	return f.glyphs[r]
+}
+
+// Width is the advance of text set in size points.
+func (f *Font) Width(text string, size float64) float64 {
+	units := 0
+	for _, r := range text {
+		units += f.advances[f.glyph(r)]
+	}
+	return float64(units) * size / float64(f.unitsPerEm)
+}
+
+// scale converts font units to the thousandths of text space PDF metrics are given in.
+func (f *Font) scale(units int) int {
+	return units * 1000 / f.unitsPerEm
+}
+
+// subsetTables are copied to subsets, layout tables are dropped as PDF positions glyphs itself.
+var subsetTables = []string{"OS/2", "cmap", "cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "name", "post", "prep"}
+
+// subset keeps the glyph ids but drops the outlines of the glyphs not in used, composite glyphs keep their parts.
+func (f *Font) subset(used map[uint16]bool) []byte {
+	keep := make(map[uint16]bool)
+	queue := []uint16{0}
+	for gid := range used {
+		queue = append(queue, gid)
+	}
+	glyf := f.tables["glyf"]
+	for len(queue) > 0 {
+		gid := queue[0]
+		queue = queue[1:]
+		if keep[gid] || int(gid) >= len(f.advances) {
+			continue
+		}
+		keep[gid] = true
+		queue = append(queue, components(glyf[f.loca[gid]:f.loca[gid+1]])...)
+	}
+
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, 4*len(f.loca))
+	for gid := 0; gid < len(f.advances); gid++ {
+		if keep[uint16(gid)] {
+			newGlyf.Write(glyf[f.loca[gid]:f.loca[gid+1]])
+			for newGlyf.Len()%4 != 0 {
+				newGlyf.WriteByte(0)
+			}
+		}
+		binary.BigEndian.PutUint32(newLoca[4*gid+4:], uint32(newGlyf.Len()))
+	}
+
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)  // checkSumAdjustment, set below
+	binary.BigEndian.PutUint16(head[50:], 1) // long loca offsets
+
+	tables := map[string][]byte{"glyf": newGlyf.Bytes(), "loca": newLoca, "head": head}
+	var tags []string
+	for _, tag := range subsetTables {
+		if tables[tag] == nil && f.tables[tag] != nil {
+			tables[tag] = f.tables[tag]
+		}
+		if tables[tag] != nil {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	var out bytes.Buffer
+	searchRange, selector := 1, 0
+	for searchRange*2 <= len(tags) {
+		searchRange *= 2
+		selector++
+	}
+	out.Write([]byte{0, 1, 0, 0})
+	writeU16(&out, len(tags), searchRange*16, selector, len(tags)*16-searchRange*16)
+
+	offset := 12 + 16*len(tags)
+	headOffset := 0
+	for _, tag := range tags {
+		data := tables[tag]
+		if tag == "head" {
+			headOffset = offset
+		}
+		out.WriteString(tag)
+		binary.Write(&out, binary.BigEndian, []uint32{checksum(data), uint32(offset), uint32(len(data))})
+		offset += (len(data) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		out.Write(tables[tag])
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	font := out.Bytes()
+	binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-checksum(font))
+	return font
+}
+
+// components lists the glyphs a composite glyph is built of.
+func components(glyph []byte) []uint16 {
+	const (
+		argsAreWords   = 0x0001
+		haveScale      = 0x0008
+		moreComponents = 0x0020
+		haveXYScale    = 0x0040
+		haveTwoByTwo   = 0x0080
+	)
+	if len(glyph) < 10 || int16(u16(glyph, 0)) >= 0 {
+		return nil
+	}
+	var gids []uint16
+	for at := 10; at+4 <= len(glyph); {
+		flags := u16(glyph, at)
+		gids = append(gids, u16(glyph, at+2))
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return gids
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+func u16(data []byte, at int) uint16 {
+	if at+2 > len(data) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(data[at:])
+}
+
+func u32(data []byte, at int) uint32 {
+	if at+4 > len(data) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(data[at:])
+}
+
+func writeU16(buf *bytes.Buffer, values ...int) {
+	for _, v := range values {
+		buf.Write([]byte{byte(v >> 8), byte(v)})
+	}
+}
@@ -0,0 +1,137 @@
+// Package peopleinfo is a client of the external People info API (GET /info?passportSerie=&passportNumber=).
+package peopleinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultTimeout          = 4 * time.Second
+	defaultMaxRetries       = 3
+	defaultBackoffBase      = 200 * time.Millisecond
+	defaultBackoffMax       = 2 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+type Info struct {
+	Surname    string `json:"surname"`
+	Name       string `json:"name"`
+	Patronymic string `json:"patronymic"`
+	Address    string `json:"address"`
+}
+
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	maxRetries  int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	breaker     *breaker
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     baseURL,
+		httpClient:  &http.Client{Timeout: defaultTimeout},
+		maxRetries:  defaultMaxRetries,
+		backoffBase: defaultBackoffBase,
+		backoffMax:  defaultBackoffMax,
+		breaker: &breaker{
+			threshold: defaultBreakerThreshold,
+			cooldown:  defaultBreakerCooldown,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// GetInfo fetches the person by passport, transient failures are retried with exponential backoff.
+func (c *Client) GetInfo(ctx context.Context, passportSerie, passportNumber string) (Info, error) {
+	if !c.breaker.allow() {
+		return Info{}, &Error{Kind: ErrUnavailable, Err: ErrCircuitOpen}
+	}
+
+	info, err := c.getInfoWithRetries(ctx, passportSerie, passportNumber)
+	switch {
+	case ctx.Err() != nil:
+		// a caller cancelling or timing out is not a failure of the API, it must not open the circuit for others
+		c.breaker.release()
+	case err != nil && errors.Is(err, ErrUnavailable):
+		c.breaker.failure()
+	default:
+		c.breaker.success()
+	}
+	return info, err
+}
+
+func (c *Client) getInfoWithRetries(ctx context.Context, passportSerie, passportNumber string) (Info, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Info{}, &Error{Kind: ErrUnavailable, Err: ctx.Err()}
+			case <-time.After(c.backoff(attempt)):
+			}
+		}
+
+		info, retryable, err := c.getInfo(ctx, passportSerie, passportNumber)
+		if err == nil || !retryable {
+			return info, err
+		}
+		lastErr = err
+	}
+	return Info{}, lastErr
+}
+
+func (c *Client) getInfo(ctx context.Context, passportSerie, passportNumber string) (Info, bool, error) {
+	query := url.Values{}
+	query.Set("passportSerie", passportSerie)
+	query.Set("passportNumber", passportNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/info?"+query.Encode(), nil)
+	if err != nil {
+		return Info{}, false, fmt.Errorf("peopleinfo - GetInfo - http.NewRequest: %w", err)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return Info{}, ctx.Err() == nil, &Error{Kind: ErrUnavailable, Err: err}
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError:
+		return Info{}, true, &Error{Kind: ErrUnavailable, StatusCode: res.StatusCode, Err: errors.New(res.Status)}
+	default:
+		return Info{}, false, &Error{Kind: ErrBadResponse, StatusCode: res.StatusCode, Err: errors.New(res.Status)}
+	}
+
+	var info Info
+	if err := json.NewDecoder(res.Body).Decode(&info); err != nil {
+		return Info{}, false, &Error{Kind: ErrBadResponse, StatusCode: res.StatusCode, Err: err}
+	}
+	return info, false, nil
+}
+
+// backoff returns the delay before the given retry with full jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.backoffBase << (attempt - 1)
+	if delay > c.backoffMax || delay <= 0 {
+		delay = c.backoffMax
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
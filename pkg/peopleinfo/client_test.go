@@ -0,0 +1,63 @@
+package peopleinfo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetInfoCancelledKeepsCircuitClosed(t *testing.T) {
+	var hang atomic.Bool
+	hang.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hang.Load() {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"surname":"Иванов","name":"Иван"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, MaxRetries(0), CircuitBreaker(2, time.Hour))
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := c.GetInfo(ctx, "1234", "567890")
+		cancel()
+		if !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: GetInfo error = %v, want the cancelled request", i, err)
+		}
+	}
+
+	hang.Store(false)
+	info, err := c.GetInfo(context.Background(), "1234", "567890")
+	if err != nil {
+		t.Fatalf("GetInfo after cancelled calls: %v", err)
+	}
+	if info.Surname != "Иванов" {
+		t.Errorf("Surname = %q, want Иванов", info.Surname)
+	}
+}
+
+func TestBreakerReleaseFreesProbe(t *testing.T) {
+	b := &breaker{threshold: 1, cooldown: 0}
+	b.failure()
+
+	if !b.allow() {
+		t.Fatal("allow = false once the cooldown has passed")
+	}
+	if b.allow() {
+		t.Fatal("allow = true while a probe is running")
+	}
+	b.release()
+	if !b.allow() {
+		t.Fatal("allow = false after the probe was released")
+	}
+	b.failure()
+	if b.failures != 2 {
+		t.Errorf("failures = %d, want 2, release must not reset them", b.failures)
+	}
+}
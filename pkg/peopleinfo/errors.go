@@ -0,0 +1,35 @@
+package peopleinfo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnavailable means the API could not be reached, timed out, kept failing or the circuit is open.
+	ErrUnavailable = errors.New("people info api unavailable")
+	// ErrBadResponse means the API answered with an unexpected status or body.
+	ErrBadResponse = errors.New("people info api bad response")
+	// ErrNotFound means a provider has no data about the passport.
+	ErrNotFound = errors.New("person not found")
+
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// Error describes a failed lookup, match its kind with errors.Is against ErrUnavailable, ErrBadResponse or ErrNotFound.
+type Error struct {
+	Kind       error
+	StatusCode int // 0 when no response was received
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%v: status %d: %v", e.Kind, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
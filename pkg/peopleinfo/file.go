@@ -0,0 +1,165 @@
+package peopleinfo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var csvColumns = []string{"passport", "surname", "name", "patronymic", "address"}
+
+// FileProvider serves people from LDAP/HR export files in a directory, the files are
+// re-read whenever one of them changes. Supported formats:
+//   - *.json: {"1234 567890": {"surname": "", "name": "", "patronymic": "", "address": ""}}
+//   - *.csv with the header passport,surname,name,patronymic,address
+type FileProvider struct {
+	dir string
+
+	mu       sync.RWMutex
+	people   map[string]Info
+	modTimes map[string]time.Time
+}
+
+func NewFileProvider(dir string) (*FileProvider, error) {
+	p := &FileProvider{dir: dir}
+	if err := p.refresh(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileProvider) GetInfo(_ context.Context, passportSerie, passportNumber string) (Info, error) {
+	if err := p.refresh(); err != nil {
+		return Info{}, &Error{Kind: ErrUnavailable, Err: err}
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	info, ok := p.people[passportSerie+" "+passportNumber]
+	if !ok {
+		return Info{}, &Error{Kind: ErrNotFound, Err: fmt.Errorf("passport not found in %s", p.dir)}
+	}
+	return info, nil
+}
+
+func (p *FileProvider) refresh() error {
+	modTimes, err := p.scan()
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	changed := !sameModTimes(p.modTimes, modTimes)
+	p.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	// files are loaded in name order, later files override earlier ones
+	paths := make([]string, 0, len(modTimes))
+	for path := range modTimes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	people := make(map[string]Info)
+	for _, path := range paths {
+		if err := loadFile(path, people); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.people, p.modTimes = people, modTimes
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *FileProvider) scan() (map[string]time.Time, error) {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, fmt.Errorf("peopleinfo - FileProvider - os.ReadDir: %w", err)
+	}
+
+	modTimes := make(map[string]time.Time)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".csv") {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("peopleinfo - FileProvider - entry.Info: %w", err)
+		}
+		modTimes[filepath.Join(p.dir, entry.Name())] = fileInfo.ModTime()
+	}
+	return modTimes, nil
+}
+
+func sameModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, modTime := range a {
+		if !b[path].Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func loadFile(path string, people map[string]Info) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("peopleinfo - loadFile - os.Open: %w", err)
+	}
+	defer f.Close()
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		if err := json.NewDecoder(f).Decode(&people); err != nil {
+			return fmt.Errorf("peopleinfo - loadFile - %s: %w", path, err)
+		}
+		return nil
+	}
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("peopleinfo - loadFile - %s: %w", path, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("peopleinfo - loadFile - %s: missing column %q", path, name)
+		}
+	}
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("peopleinfo - loadFile - %s: %w", path, err)
+		}
+		people[strings.TrimSpace(record[columns["passport"]])] = Info{
+			Surname:    record[columns["surname"]],
+			Name:       record[columns["name"]],
+			Patronymic: record[columns["patronymic"]],
+			Address:    record[columns["address"]],
+		}
+	}
+}
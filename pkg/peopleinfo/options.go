@@ -0,0 +1,35 @@
+package peopleinfo
+
+import "time"
+
+type Option func(*Client)
+
+// Timeout limits a single attempt, the whole call is bounded by the caller's context.
+func Timeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+func MaxRetries(retries int) Option {
+	return func(c *Client) {
+		c.maxRetries = retries
+	}
+}
+
+// Backoff sets the delay before the first retry, it doubles on every next one up to max.
+func Backoff(base, max time.Duration) Option {
+	return func(c *Client) {
+		c.backoffBase = base
+		c.backoffMax = max
+	}
+}
+
+// CircuitBreaker opens the circuit after threshold consecutive failed calls and
+// lets a probe call through once cooldown has passed.
+func CircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker.threshold = threshold
+		c.breaker.cooldown = cooldown
+	}
+}
@@ -0,0 +1,45 @@
+package peopleinfo
+
+import (
+	"context"
+	"errors"
+)
+
+// Provider looks a person up by passport, Client queries the HTTP API and FileProvider local export files.
+type Provider interface {
+	GetInfo(ctx context.Context, passportSerie, passportNumber string) (Info, error)
+}
+
+type chain []Provider
+
+// Chain asks providers in priority order and returns the first found person.
+// When nobody knows the person the result is ErrUnavailable if any provider was unavailable,
+// otherwise the error of the last provider.
+func Chain(providers ...Provider) Provider {
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return chain(providers)
+}
+
+func (c chain) GetInfo(ctx context.Context, passportSerie, passportNumber string) (Info, error) {
+	var lastErr, unavailableErr error
+	for _, provider := range c {
+		info, err := provider.GetInfo(ctx, passportSerie, passportNumber)
+		if err == nil {
+			return info, nil
+		}
+		if errors.Is(err, ErrUnavailable) {
+			unavailableErr = err
+		}
+		lastErr = err
+	}
+
+	if unavailableErr != nil {
+		return Info{}, unavailableErr
+	}
+	if lastErr == nil {
+		return Info{}, &Error{Kind: ErrNotFound, Err: errors.New("no providers configured")}
+	}
+	return Info{}, lastErr
+}
@@ -0,0 +1,62 @@
+// Package pii hides personal data in values shown to callers and written to logs.
+package pii
+
+import (
+	"regexp"
+	"strings"
+	"time-tracker/pkg/document"
+	"unicode"
+)
+
+const visibleChars = 3
+
+var (
+	documentPattern = scanPattern(document.DefaultRegistry().Patterns())
+	emailPattern    = regexp.MustCompile(`[\w.%+-]+@[\w-]+(\.[\w-]+)+`)
+	// errors of postgres, strconv and encoding/json quote the offending value, such as an address
+	quotedPattern = regexp.MustCompile(`"[^"]*"`)
+)
+
+// Mask replaces every letter and digit of value except the last three with '*',
+// separators are kept so the shape stays recognisable: "1234 567456" -> "**** ***456".
+func Mask(value string) string {
+	runes := []rune(value)
+
+	visible := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if visible < visibleChars {
+			visible++
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// Scrub masks document numbers, email addresses and double quoted values found in free text such as log
+// messages.
+func Scrub(text string) string {
+	text = quotedPattern.ReplaceAllStringFunc(text, Mask)
+	text = emailPattern.ReplaceAllStringFunc(text, Mask)
+	return documentPattern.ReplaceAllStringFunc(text, func(match string) string {
+		// the generic foreign passport pattern also matches words such as "SELECT", numbers have digits
+		if !strings.ContainsAny(match, "0123456789") {
+			return match
+		}
+		return Mask(match)
+	})
+}
+
+// scanPattern finds numbers of the anchored document patterns in free text. Numbers may be logged before they
+// are normalized, so spaces are optional and case is ignored.
+func scanPattern(patterns []*regexp.Regexp) *regexp.Regexp {
+	alternatives := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		source := strings.TrimSuffix(strings.TrimPrefix(pattern.String(), "^"), "$")
+		alternatives[i] = strings.ReplaceAll(source, " ", `\s?`)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(alternatives, "|") + `)\b`)
+}
@@ -0,0 +1,48 @@
+package pii
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", ""},
+		{"ab", "ab"},
+		{"1234 567456", "**** ***456"},
+		{"C01X00T47", "******T47"},
+		{"Иванов", "***нов"},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.value); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"ru passport", "lookup 1234 567890 failed", "lookup **** ***890 failed"},
+		{"ru passport without space", "lookup 1234567890 failed", "lookup *******890 failed"},
+		{"ru foreign passport", "foreign_passport 75 1234567 is invalid", "foreign_passport ** ****567 is invalid"},
+		{"ru residence permit", "residence_permit 82 0012345 is invalid", "residence_permit ** ****345 is invalid"},
+		{"de foreign passport", "passport C01X00T47 is invalid", "passport ******T47 is invalid"},
+		{"us foreign passport", "passport 56789012A is invalid", "passport ******12A is invalid"},
+		{"generic foreign passport", "passport AB12345 is invalid", "passport ****345 is invalid"},
+		{"not normalized", "passport c01x00t47 is invalid", "passport ******t47 is invalid"},
+		{"email", "duplicate ivan.ivanov@example.com", "duplicate ****.******@*******.com"},
+		{"quoted", `invalid input "Тверская, 1"`, `invalid input "******ая, 1"`},
+		{"words are kept", "SELECT users FAILED: deadline exceeded", "SELECT users FAILED: deadline exceeded"},
+		{"short numbers are kept", "user 42 task 12345", "user 42 task 12345"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scrub(tt.text); got != tt.want {
+				t.Errorf("Scrub(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package publish hands domain events over to the log, an NDJSON file or other consumers.
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Message is a domain event, ID is unique and increases within an aggregate. A message may be published more
+// than once, consumers deduplicate it by ID.
+type Message struct {
+	ID          int64           `json:"id"`
+	Aggregate   string          `json:"aggregate"`
+	AggregateID int             `json:"aggregate_id"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, m Message) error
+}
+
+type multi []Publisher
+
+// Multi publishes through every publisher, the message is unpublished if any of them failed.
+func Multi(publishers ...Publisher) Publisher {
+	if len(publishers) == 1 {
+		return publishers[0]
+	}
+	return multi(publishers)
+}
+
+func (m multi) Publish(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes messages to the application log.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Publish(_ context.Context, m Message) error {
+	log.WithFields(log.Fields{
+		"id":           m.ID,
+		"aggregate":    m.Aggregate,
+		"aggregate_id": m.AggregateID,
+		"payload":      string(m.Payload),
+	}).Info(m.Event)
+	return nil
+}
+
+// File appends messages to a file as newline delimited JSON, every message is synced to disk before Publish
+// returns.
+type File struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("publish - NewFile - os.OpenFile: %w", err)
+	}
+	return &File{file: file}, nil
+}
+
+func (f *File) Publish(_ context.Context, m Message) error {
+	line, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("publish - File.Publish - json.Marshal: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("publish - File.Publish - file.Write: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("publish - File.Publish - file.Sync: %w", err)
+	}
+	return nil
+}
+
+func (f *File) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,23 @@
+package scheduler
+
+import "time"
+
+type Option func(*Scheduler)
+
+func Interval(interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = interval
+	}
+}
+
+func ErrorHandler(handler func(error)) Option {
+	return func(s *Scheduler) {
+		s.errorHandler = handler
+	}
+}
+
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Scheduler) {
+		s.shutdownTimeout = timeout
+	}
+}
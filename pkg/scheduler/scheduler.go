@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultInterval        = time.Minute
+	defaultShutdownTimeout = 3 * time.Second
+)
+
+var ErrShutdownTimeout = errors.New("scheduler: shutdown timeout")
+
+type Job func(ctx context.Context) error
+
+// Scheduler runs a job periodically until Shutdown is called.
+type Scheduler struct {
+	job             Job
+	interval        time.Duration
+	shutdownTimeout time.Duration
+	errorHandler    func(error)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(job Job, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		job:             job,
+		interval:        defaultInterval,
+		shutdownTimeout: defaultShutdownTimeout,
+		errorHandler:    func(error) {},
+		done:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.start()
+	return s
+}
+
+func (s *Scheduler) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.run(ctx); err != nil && ctx.Err() == nil {
+				s.errorHandler(err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// run returns a panic of the job as an error, so that it neither stops the scheduler nor crashes the process.
+func (s *Scheduler) run(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scheduler: job panicked: %v", r)
+		}
+	}()
+	return s.job(ctx)
+}
+
+// Shutdown cancels the running job and waits for it to return.
+func (s *Scheduler) Shutdown() error {
+	s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(s.shutdownTimeout):
+		return ErrShutdownTimeout
+	}
+}
@@ -0,0 +1,248 @@
+// Package tracker reads time entries from the detailed CSV exports of other time trackers: Toggl Track,
+// Clockify and Harvest. Exports carry local times without an offset, they are read in the given location.
+package tracker
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatToggl    = "toggl"
+	FormatClockify = "clockify"
+	FormatHarvest  = "harvest"
+)
+
+var (
+	// ErrInvalidFile wraps the errors of files that can not be read at all, bad rows are reported in Entry.Err
+	// instead.
+	ErrInvalidFile = errors.New("invalid tracker export")
+	// ErrTooManyEntries is returned by Read once the export has more entries than allowed.
+	ErrTooManyEntries = errors.New("too many tracker entries")
+)
+
+type Entry struct {
+	Line        int
+	ID          string // id of the entry in the tracker, empty when the export has none
+	User        string // full name as the tracker shows it
+	Email       string
+	Project     string
+	Description string
+	Billable    bool // true when the export does not tell
+	Start       time.Time
+	End         time.Time
+	// Err is set when the row could not be read, the other fields may be incomplete then
+	Err error
+}
+
+// layout maps the columns of a tracker export, detect holds columns only its header has.
+type layout struct {
+	format string
+	detect []string
+	read   func(row row, loc *time.Location) (Entry, error)
+}
+
+var layouts = []layout{
+	{
+		format: FormatToggl,
+		detect: []string{"Start date", "Start time", "End date", "End time"},
+		read: func(row row, loc *time.Location) (Entry, error) {
+			return readInterval(row, loc, "Start date", "Start time", "End date", "End time")
+		},
+	},
+	{
+		format: FormatClockify,
+		detect: []string{"Start Date", "Start Time", "End Date", "End Time"},
+		read: func(row row, loc *time.Location) (Entry, error) {
+			return readInterval(row, loc, "Start Date", "Start Time", "End Date", "End Time")
+		},
+	},
+	{
+		format: FormatHarvest,
+		detect: []string{"Date", "Hours", "First Name", "Last Name"},
+		read:   readHarvest,
+	},
+}
+
+// dateLayouts are tried in order, so slashed dates are taken as month first like Clockify writes them by default.
+var (
+	dateLayouts = []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+	timeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "3:04:05 PM", "03:04 PM", "3:04 PM"}
+)
+
+// Formats lists the supported export formats.
+func Formats() []string {
+	formats := make([]string, len(layouts))
+	for i, l := range layouts {
+		formats[i] = l.format
+	}
+	return formats
+}
+
+// Read reads every entry of the export, format is detected from the header when it is empty.
+// The detected or given format is returned with the entries. Reading stops with ErrTooManyEntries at the entry
+// after maxEntries.
+func Read(r io.Reader, format string, loc *time.Location, maxEntries int) (string, []Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: could not read csv header: %w", ErrInvalidFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// spreadsheet apps prepend a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	l, err := findLayout(columns, format)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return l.format, entries, nil
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: could not read csv: %w", ErrInvalidFile, err)
+		}
+		if len(entries) == maxEntries {
+			return "", nil, ErrTooManyEntries
+		}
+		line, _ := reader.FieldPos(0)
+		row := row{columns, record}
+
+		entry, err := l.read(row, loc)
+		entry.Line = line
+		entry.ID = row.get("ID")
+		entry.Project = row.get("Project")
+		entry.Billable = billable(row)
+		entry.Err = err
+		if entry.Description == "" {
+			entry.Description = row.get("Task")
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func findLayout(columns map[string]int, format string) (layout, error) {
+	for _, l := range layouts {
+		if format != "" && l.format != format {
+			continue
+		}
+		missing := ""
+		for _, name := range l.detect {
+			if _, ok := columns[name]; !ok {
+				missing = name
+				break
+			}
+		}
+		if missing == "" {
+			return l, nil
+		}
+		if format != "" {
+			return layout{}, fmt.Errorf("%w: %s export has no %q column", ErrInvalidFile, format, missing)
+		}
+	}
+	if format != "" {
+		return layout{}, fmt.Errorf("%w: unknown format %q", ErrInvalidFile, format)
+	}
+	return layout{}, fmt.Errorf("%w: columns do not match %s exports", ErrInvalidFile, strings.Join(Formats(), ", "))
+}
+
+type row struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r row) get(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// readInterval reads Toggl and Clockify rows, which have the same fields under differently cased columns.
+func readInterval(row row, loc *time.Location, startDate, startTime, endDate, endTime string) (Entry, error) {
+	entry := Entry{
+		User:        row.get("User"),
+		Email:       row.get("Email"),
+		Description: row.get("Description"),
+	}
+	if entry.User == "" {
+		// newer Toggl exports name the column after workspace members
+		entry.User = row.get("Member")
+	}
+	var err error
+	entry.Start, err = parseDateTime(row.get(startDate), row.get(startTime), loc)
+	if err != nil {
+		return entry, fmt.Errorf("start: %v", err)
+	}
+	entry.End, err = parseDateTime(row.get(endDate), row.get(endTime), loc)
+	if err != nil {
+		return entry, fmt.Errorf("end: %v", err)
+	}
+	if entry.End.Before(entry.Start) {
+		return entry, errors.New("end is before start")
+	}
+	return entry, nil
+}
+
+// readHarvest reads a Harvest row, which has a day and hours only, so the entry starts at the beginning of the day.
+func readHarvest(row row, loc *time.Location) (Entry, error) {
+	entry := Entry{
+		User:        strings.TrimSpace(row.get("First Name") + " " + row.get("Last Name")),
+		Description: row.get("Notes"),
+	}
+	var err error
+	entry.Start, err = parseDateTime(row.get("Date"), "00:00", loc)
+	if err != nil {
+		return entry, fmt.Errorf("date: %v", err)
+	}
+	hours, err := strconv.ParseFloat(strings.Replace(row.get("Hours"), ",", ".", 1), 64)
+	if err != nil || hours < 0 || hours > 24 {
+		return entry, fmt.Errorf("invalid hours %q", row.get("Hours"))
+	}
+	entry.End = entry.Start.Add(time.Duration(hours * float64(time.Hour)))
+	return entry, nil
+}
+
+// billable reads the Yes/No column Toggl and Clockify name Billable and Harvest Billable?.
+func billable(row row) bool {
+	value := row.get("Billable")
+	if value == "" {
+		value = row.get("Billable?")
+	}
+	return !strings.EqualFold(value, "no") && !strings.EqualFold(value, "false")
+}
+
+func parseDateTime(date, clock string, loc *time.Location) (time.Time, error) {
+	for _, dateLayout := range dateLayouts {
+		day, err := time.ParseInLocation(dateLayout, date, loc)
+		if err != nil {
+			continue
+		}
+		for _, timeLayout := range timeLayouts {
+			t, err := time.ParseInLocation(dateLayout+" "+timeLayout, date+" "+strings.ToUpper(clock), loc)
+			if err == nil {
+				return t, nil
+			}
+		}
+		return day, fmt.Errorf("invalid time %q", clock)
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", date)
+}